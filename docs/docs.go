@@ -15,21 +15,21 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/healthcheck": {
+        "/api/v1/categories": {
             "get": {
-                "description": "Retrieves status of the application",
+                "description": "Retrieves all categories pastes can be put in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "app"
+                    "categories"
                 ],
-                "summary": "Health check",
+                "summary": "List categories",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved paste",
+                        "description": "Successfully retrieved categories",
                         "schema": {
-                            "$ref": "#/definitions/v1.HealthCheckOutput"
+                            "$ref": "#/definitions/v1.ListCategoriesOutput"
                         }
                     },
                     "429": {
@@ -45,79 +45,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/pastes/": {
-            "get": {
-                "description": "Retrieves a paste from the database by its ID.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "pastes"
-                ],
-                "summary": "Retrieve a paste",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Title of the paste",
-                        "name": "title",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Category ID of the paste",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort order, e.g., -created_at",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number for pagination",
-                        "name": "page",
-                        "in": "query"
-                    },
+            },
+            "post": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "pageSize",
-                        "in": "query"
+                        "Bearer": []
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "Successfully retrieved paste",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ListPastesOutput"
-                        }
-                    },
-                    "422": {
-                        "description": "Unprocessing data",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Too many requests, rate limit exceeded",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new paste in the database by input data.",
+                "description": "Creates a new category. Available to administrators only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -125,25 +60,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pastes"
+                    "categories"
                 ],
-                "summary": "Create a new paste",
+                "summary": "Create a category",
                 "parameters": [
                     {
-                        "description": "Paste creation input",
+                        "description": "Category input",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreatePasteInput"
+                            "$ref": "#/definitions/v1.CategoryInput"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Successfully created paste",
+                        "description": "Successfully created category",
                         "schema": {
-                            "$ref": "#/definitions/v1.PasteResp"
+                            "$ref": "#/definitions/v1.CategoryResp"
                         }
                     },
                     "400": {
@@ -152,55 +87,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable data",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Too many requests, rate limit exceeded",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "User is not an administrator",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/pastes/{id}": {
-            "get": {
-                "description": "Retrieves a paste from the database by its ID.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "pastes"
-                ],
-                "summary": "Retrieve a paste",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Paste ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successfully retrieved paste",
-                        "schema": {
-                            "$ref": "#/definitions/v1.PasteResp"
-                        }
                     },
-                    "404": {
-                        "description": "Paste not found",
+                    "422": {
+                        "description": "Unprocessable data",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -218,25 +112,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/categories/{id}": {
             "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Deletes a paste from the database by its ID.",
+                "description": "Deletes a category which has no pastes. Available to administrators only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pastes"
+                    "categories"
                 ],
-                "summary": "Deletes a paste",
+                "summary": "Delete a category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Paste ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -244,16 +140,22 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Successfully deleted paste"
+                        "description": "Successfully deleted category"
                     },
                     "403": {
-                        "description": "User is not allowed to edit this paste",
+                        "description": "User is not an administrator",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Paste not found",
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Category has pastes",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -278,7 +180,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Updates the paste in the database by ID and input data.",
+                "description": "Changes the name of a category. Available to administrators only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -286,31 +188,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pastes"
+                    "categories"
                 ],
-                "summary": "Update the paste",
+                "summary": "Rename a category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Paste ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Paste update input",
+                        "description": "Category input",
                         "name": "body",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdatePasteInput"
+                            "$ref": "#/definitions/v1.CategoryInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully updated paste",
+                        "description": "Successfully updated category",
                         "schema": {
-                            "$ref": "#/definitions/v1.PasteResp"
+                            "$ref": "#/definitions/v1.CategoryResp"
                         }
                     },
                     "400": {
@@ -320,13 +223,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "User is not allowed to edit this paste",
+                        "description": "User is not an administrator",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Category not found",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -352,54 +255,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tokens/authentication": {
-            "post": {
-                "description": "Creates a new user token in the database by input data.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/healthcheck": {
+            "get": {
+                "description": "Retrieves status of the application",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users",
-                    "tokens"
-                ],
-                "summary": "Authentication",
-                "parameters": [
-                    {
-                        "description": "User registration input",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.AuthInput"
-                        }
-                    }
+                    "app"
                 ],
+                "summary": "Health check",
                 "responses": {
-                    "201": {
-                        "description": "Successfully created",
-                        "schema": {
-                            "$ref": "#/definitions/v1.AuthResp"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Unprocessable data",
+                    "200": {
+                        "description": "Successfully retrieved paste",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/v1.HealthCheckOutput"
                         }
                     },
                     "429": {
@@ -417,56 +287,2255 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/": {
-            "post": {
-                "description": "Creates a new user in the database by input data.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/pastes/": {
+            "get": {
+                "description": "Retrieves public pastes from the database. Unlisted and private pastes are left out.\nThe q parameter searches both titles and texts in the natural language of each paste or only in the given one,\nthe results are sorted by relevance\nunless another sort is given and carry snippets of the matching text with the matches wrapped in mark tags.\nText of protected pastes and of pastes with limited views is not searched.\nBesides page numbers, the listing can be paged with the cursors from next_cursor and prev_cursor\npassed in the after and before parameters, an empty after starts from the first page.\nCursor pages are not shifted by new pastes. The total and the last page are returned only if count is set.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "pastes"
                 ],
-                "summary": "Registration",
+                "summary": "List pastes",
                 "parameters": [
                     {
-                        "description": "User registration input",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.RegistrationInput"
-                        }
-                    }
-                ],
-                "responses": {
-                    "202": {
-                        "description": "Successfully accepted",
-                        "schema": {
-                            "$ref": "#/definitions/v1.UserResp"
-                        }
+                        "type": "string",
+                        "description": "Search query, e.g. \\",
+                        "name": "q",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
+                    {
+                        "type": "string",
+                        "description": "Title of the paste",
+                        "name": "title",
+                        "in": "query"
                     },
-                    "422": {
-                        "description": "Unprocessable data",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
+                    {
+                        "type": "integer",
+                        "description": "Category ID of the paste",
+                        "name": "category",
+                        "in": "query"
                     },
-                    "429": {
-                        "description": "Too many requests, rate limit exceeded",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
+                    {
+                        "type": "string",
+                        "description": "Language of the paste, e.g. go",
+                        "name": "language",
+                        "in": "query"
                     },
-                    "500": {
+                    {
+                        "type": "string",
+                        "description": "Natural language of the paste and the search query, e.g. english",
+                        "name": "natural_language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags, e.g. go,sql",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether pastes must have any or all of the tags, any by default",
+                        "name": "tags_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order, e.g., -created_at, or -rank with q",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to start after",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to end before",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count the total of the matching pastes, false by default",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached listing",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListPastesOutput"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the listing"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Listing has not changed"
+                    },
+                    "422": {
+                        "description": "Unprocessing data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new paste in the database by input data.\nBesides JSON, the text can be sent as a text/plain body or as the file field of a multipart/form-data upload.\nThen the other fields are read from form fields, query parameters or X-Paste-* headers, e.g. X-Paste-Max-Views.\nThe password is read from the form field or the X-Paste-Password header only.\nThe language is detected from the title, the shebang line and keywords unless it is set.\nThe format defaults to markdown for Markdown pastes and to plain for the others.\nThe natural language the paste is searched in is detected from the text unless it is set.",
+                "consumes": [
+                    "application/json",
+                    "text/plain",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pastes"
+                ],
+                "summary": "Create a new paste",
+                "parameters": [
+                    {
+                        "description": "Paste creation input",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreatePasteInput"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Paste text file, its name is the default title",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title of a text/plain or multipart paste",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category of a text/plain or multipart paste",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lifetime of a text/plain or multipart paste",
+                        "name": "minutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of a text/plain or multipart paste, detected if omitted",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of a text/plain or multipart paste, plain or markdown",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags of a text/plain or multipart paste",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Natural language of a text/plain or multipart paste, detected if omitted",
+                        "name": "natural_language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Visibility of a text/plain or multipart paste",
+                        "name": "visibility",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Burn a text/plain or multipart paste after reading",
+                        "name": "burn_after_read",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "View limit of a text/plain or multipart paste",
+                        "name": "max_views",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a text/plain or multipart paste",
+                        "name": "X-Paste-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PasteResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}": {
+            "get": {
+                "description": "Retrieves a paste from the database by its slug.\nProtected pastes require the password unless the user is the owner or a collaborator.\nPastes with limited views are deleted after the last allowed read.\nConditional requests are answered with 304 without counting a view.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pastes"
+                ],
+                "summary": "Retrieve a paste",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected paste",
+                        "name": "X-Paste-Password",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached paste",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the cached paste",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PasteResp"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching rules based on the paste expiry"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the paste version"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Time of the last update"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Paste has not changed"
+                    },
+                    "401": {
+                        "description": "Paste password required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid paste password",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a paste from the database by its slug.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pastes"
+                ],
+                "summary": "Deletes a paste",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully deleted paste"
+                    },
+                    "403": {
+                        "description": "User is not the owner of this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates the paste in the database by slug and input data.\nThe version the client has edited can be passed as an ETag in the If-Match header or in the version field.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pastes"
+                ],
+                "summary": "Update the paste",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the edited version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Paste update input",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdatePasteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PasteResp"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the new paste version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not allowed to edit this paste or to change its visibility",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Edit conflict, the version field does not match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The If-Match header does not match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/collaborators": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves users who were granted access to the paste and their roles.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collaborators"
+                ],
+                "summary": "List collaborators",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved collaborators",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListCollaboratorsOutput"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not allowed to edit this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Grants a user found by login or email read or write access to the paste and notifies them by email.\nGranting access to an existing collaborator changes their role. The user account must be activated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collaborators"
+                ],
+                "summary": "Add a collaborator",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collaborator and role, write by default",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CollaboratorInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully changed the role",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CollaboratorResp"
+                        }
+                    },
+                    "201": {
+                        "description": "Successfully added collaborator",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CollaboratorResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not the owner of this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes access to the paste from a user found by login or email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collaborators"
+                ],
+                "summary": "Remove a collaborator",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collaborator",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.RemoveCollaboratorInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully removed collaborator"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not the owner of this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste or collaborator not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the unified diff of the paste text between two versions.\nBy default the current version is compared with the previous one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Compare revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Old version",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "New version",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully compared revisions",
+                        "schema": {
+                            "$ref": "#/definitions/v1.DiffOutput"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not allowed to edit this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/html": {
+            "get": {
+                "description": "Renders a standalone HTML page with the paste text highlighted according to its language.\nLines are numbered and can be linked with anchors like #L10 or #L10-L20.\nExpiry, visibility, password and view limits are the same as for the JSON representation.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "pastes"
+                ],
+                "summary": "Retrieve a paste as HTML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Color theme, light or dark",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected paste",
+                        "name": "X-Paste-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Paste has not changed"
+                    },
+                    "401": {
+                        "description": "Paste password required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid paste password",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/owner": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Makes another user the owner of the paste. Only the current owner can do it.\nThe version of the paste stays the same, the ETag changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pastes"
+                ],
+                "summary": "Transfer the paste",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the transferred version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New owner",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TransferPasteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully transferred paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PasteResp"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the transferred paste"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not the owner of this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Edit conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The If-Match header does not match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/raw": {
+            "get": {
+                "description": "Retrieves the text of a paste as text/plain. Supports range requests.\nExpiry, visibility, password and view limits are the same as for the JSON representation,\nso every request to a paste with limited views, including range requests, is counted.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "pastes"
+                ],
+                "summary": "Retrieve a raw paste",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Send the paste as an attachment",
+                        "name": "download",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected paste",
+                        "name": "X-Paste-Password",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached paste",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the cached paste",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte ranges of the text",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paste text",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "Content-Disposition": {
+                                "type": "string",
+                                "description": "Suggested file name"
+                            }
+                        }
+                    },
+                    "206": {
+                        "description": "Requested ranges of the paste text",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Paste has not changed"
+                    },
+                    "401": {
+                        "description": "Paste password required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid paste password",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "416": {
+                        "description": "Requested range not satisfiable"
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/render": {
+            "get": {
+                "description": "Converts a Markdown paste to HTML sanitized against an allowlist, so embedded scripts\nand frames are removed, and returns it as a standalone page. Plain pastes are shown\nthe same way as by the HTML endpoint. The rendered HTML is cached until the paste changes.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "pastes"
+                ],
+                "summary": "Render a paste",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Color theme, light or dark",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected paste",
+                        "name": "X-Paste-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Paste has not changed"
+                    },
+                    "401": {
+                        "description": "Paste password required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid paste password",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves all versions of the paste, newest first, without their text.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "List revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved revisions",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListRevisionsOutput"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not allowed to edit this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/revisions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves the title and text of the paste at the given version.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Retrieve a revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Paste version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved revision",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RevisionResp"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not allowed to edit this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pastes/{slug}/revisions/{version}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Saves the title and text of the given version as a new version of the paste.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Restore a revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Paste slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Paste version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the current version",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully restored revision",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PasteResp"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the new paste version"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not allowed to edit this paste",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Paste or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Edit conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The If-Match header does not match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tags": {
+            "get": {
+                "description": "Retrieves the tags of public pastes with the number of pastes for each, the most used first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of tags, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved tags",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListTagsOutput"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tokens/activation": {
+            "post": {
+                "description": "Revokes the activation codes sent before and sends a new one to the email if an account\nwhich is not activated yet uses it. The response is the same whether the account exists or not.\nEmails to one address are rate limited.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Activation email resend",
+                "parameters": [
+                    {
+                        "description": "Activation email resend input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ActivationTokenInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully accepted",
+                        "schema": {
+                            "$ref": "#/definitions/v1.MessageResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tokens/authentication": {
+            "post": {
+                "description": "Creates a new user token in the database by input data.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users",
+                    "tokens"
+                ],
+                "summary": "Authentication",
+                "parameters": [
+                    {
+                        "description": "User registration input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuthInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuthResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes the authentication token of the request. Other sessions of the user stay valid.",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "204": {
+                        "description": "Successfully revoked"
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tokens/password-reset": {
+            "post": {
+                "description": "Sends a password reset code to the email if an account uses it.\nThe response is the same whether the account exists or not, so it does not tell which emails are registered.\nEmails to one address are rate limited.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Password reset request",
+                "parameters": [
+                    {
+                        "description": "Password reset request input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.PasswordResetInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully accepted",
+                        "schema": {
+                            "$ref": "#/definitions/v1.MessageResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/": {
+            "post": {
+                "description": "Creates a new user in the database by input data.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Registration",
+                "parameters": [
+                    {
+                        "description": "User registration input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.RegistrationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully accepted",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/activated/": {
+            "put": {
+                "description": "Activates the user by input token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Activation",
+                "parameters": [
+                    {
+                        "description": "User activation input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ActivateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully accepted",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/email": {
+            "put": {
+                "description": "Confirms the new email of the user by the code sent to it and makes it the email of the account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Email confirmation",
+                "parameters": [
+                    {
+                        "description": "Email confirmation input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConfirmEmailInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully changed email",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Edit conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves the profile of the current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "My profile",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved profile",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes the current user together with all their tokens. The pastes they own are kept without an owner.\nThe current password is required, so a stolen token is not enough to delete the account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete my account",
+                "parameters": [
+                    {
+                        "description": "Account deletion input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DeleteUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully deleted account"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The current password does not match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes the login and the email of the current user. The login is changed at once,\nwhile the new email is kept as pending_email and replaces the email only after it is\nconfirmed with the code sent to the new address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update my profile",
+                "parameters": [
+                    {
+                        "description": "Profile update input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated profile",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Inactive account",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Edit conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes the password of the current user, who has to send the current one.\nEvery authentication token of the user is revoked, so the user has to sign in again.\nPassword reset and email change codes sent before are revoked as well.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change my password",
+                "parameters": [
+                    {
+                        "description": "Password change input",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ChangePasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully changed password"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Edit conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/pastes": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves the pastes the current user owns or collaborates on, including unlisted and private ones.\nThe role of the user is returned with each paste. Search, sort and paging are the same as for all pastes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List my pastes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role of the user, owner or collaborator",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order, e.g., -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to start after",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to end before",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count the total of the matching pastes, false by default",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved pastes",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListPastesOutput"
+                        }
+                    },
+                    "304": {
+                        "description": "Listing has not changed"
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Inactive account",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves the unexpired authentication tokens of the current user, the recently used first.\nThe session the request is authenticated by is marked as current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List my sessions",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved sessions",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SessionsResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
                         "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
@@ -475,9 +2544,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/activated/": {
+        "/api/v1/users/me/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes the authentication token with the ID, e.g., one left on a lost device.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Revoke my session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully revoked"
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/password": {
             "put": {
-                "description": "Activates the user by input token.",
+                "description": "Sets the new password of the user by the code from the password reset email.\nEvery token of the user is revoked, so the user has to sign in again on all devices.",
                 "consumes": [
                     "application/json"
                 ],
@@ -487,23 +2608,23 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Activation",
+                "summary": "Password reset",
                 "parameters": [
                     {
-                        "description": "User activation input",
+                        "description": "Password reset input",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.ActivateUserInput"
+                            "$ref": "#/definitions/v1.ResetPasswordInput"
                         }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Successfully accepted",
+                    "200": {
+                        "description": "Successfully reset password",
                         "schema": {
-                            "$ref": "#/definitions/v1.UserResp"
+                            "$ref": "#/definitions/v1.MessageResp"
                         }
                     },
                     "400": {
@@ -512,6 +2633,116 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Edit conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests, rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{login}/pastes": {
+            "get": {
+                "description": "Retrieves the public pastes the user owns or collaborates on.\nSearch, sort and paging are the same as for all pastes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List pastes of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User login",
+                        "name": "login",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role of the user, owner or collaborator",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order, e.g., -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to start after",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to end before",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count the total of the matching pastes, false by default",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved pastes",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListPastesOutput"
+                        }
+                    },
+                    "304": {
+                        "description": "Listing has not changed"
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable data",
                         "schema": {
@@ -532,51 +2763,184 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Metadata": {
+        }
+    },
+    "definitions": {
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Collaborator": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Metadata": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "first_page": {
+                    "type": "integer"
+                },
+                "last_page": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "total_records": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Paste": {
+            "type": "object",
+            "properties": {
+                "burn_after_read": {
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "max_views": {
+                    "type": "integer"
+                },
+                "natural_language": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "integer"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "views": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Revision": {
             "type": "object",
             "properties": {
-                "current_page": {
-                    "type": "integer"
-                },
-                "first_page": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "last_page": {
-                    "type": "integer"
+                "text": {
+                    "type": "string"
                 },
-                "page_size": {
-                    "type": "integer"
+                "title": {
+                    "type": "string"
                 },
-                "total_records": {
+                "version": {
                     "type": "integer"
                 }
             }
         },
-        "models.Paste": {
+        "models.Session": {
             "type": "object",
             "properties": {
-                "category": {
-                    "type": "integer"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "expires_at": {
+                "current": {
+                    "type": "boolean"
+                },
+                "expiry": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "text": {
+                "ip": {
                     "type": "string"
                 },
-                "title": {
+                "last_used_at": {
                     "type": "string"
                 },
-                "version": {
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagCount": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "pastes": {
                     "type": "integer"
                 }
             }
@@ -598,6 +2962,9 @@ const docTemplate = `{
                 "activated": {
                     "type": "boolean"
                 },
+                "admin": {
+                    "type": "boolean"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -609,6 +2976,9 @@ const docTemplate = `{
                 },
                 "login": {
                     "type": "string"
+                },
+                "pending_email": {
+                    "type": "string"
                 }
             }
         },
@@ -620,6 +2990,14 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.ActivationTokenInput": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.AuthInput": {
             "type": "object",
             "properties": {
@@ -639,20 +3017,126 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.CategoryInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CategoryResp": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/models.Category"
+                }
+            }
+        },
+        "v1.ChangePasswordInput": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CollaboratorInput": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CollaboratorResp": {
+            "type": "object",
+            "properties": {
+                "collaborator": {
+                    "$ref": "#/definitions/models.Collaborator"
+                }
+            }
+        },
+        "v1.ConfirmEmailInput": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.CreatePasteInput": {
             "type": "object",
             "properties": {
+                "burn_after_read": {
+                    "type": "boolean"
+                },
                 "category": {
                     "type": "integer"
                 },
+                "format": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "max_views": {
+                    "type": "integer"
+                },
                 "minutes": {
                     "type": "integer"
                 },
+                "natural_language": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "text": {
                     "type": "string"
                 },
                 "title": {
                     "type": "string"
+                },
+                "visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.DeleteUserInput": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.DiffOutput": {
+            "type": "object",
+            "properties": {
+                "diff": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
                 }
             }
         },
@@ -683,6 +3167,28 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.ListCategoriesOutput": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                }
+            }
+        },
+        "v1.ListCollaboratorsOutput": {
+            "type": "object",
+            "properties": {
+                "collaborators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Collaborator"
+                    }
+                }
+            }
+        },
         "v1.ListPastesOutput": {
             "type": "object",
             "properties": {
@@ -697,6 +3203,44 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.ListRevisionsOutput": {
+            "type": "object",
+            "properties": {
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Revision"
+                    }
+                }
+            }
+        },
+        "v1.ListTagsOutput": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagCount"
+                    }
+                }
+            }
+        },
+        "v1.MessageResp": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.PasswordResetInput": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.PasteResp": {
             "type": "object",
             "properties": {
@@ -719,20 +3263,104 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.RemoveCollaboratorInput": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ResetPasswordInput": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.RevisionResp": {
+            "type": "object",
+            "properties": {
+                "revision": {
+                    "$ref": "#/definitions/models.Revision"
+                }
+            }
+        },
+        "v1.SessionsResp": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Session"
+                    }
+                }
+            }
+        },
+        "v1.TransferPasteInput": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdatePasteInput": {
             "type": "object",
             "properties": {
                 "category": {
                     "type": "integer"
                 },
+                "format": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
                 "minutes": {
                     "type": "integer"
                 },
+                "natural_language": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "text": {
                     "type": "string"
                 },
                 "title": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.UpdateUserInput": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
                 }
             }
         },
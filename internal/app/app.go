@@ -1,10 +1,14 @@
 package app
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"pasteAPI/internal/config"
 	"pasteAPI/internal/http/v1"
 	"pasteAPI/internal/metrics"
 	"pasteAPI/internal/repository"
+	"pasteAPI/internal/repository/models"
 	"pasteAPI/internal/server"
 	"pasteAPI/internal/service"
 	"pasteAPI/pkg/logger"
@@ -28,12 +32,47 @@ func Run(cfg *config.Config) {
 	metrics.PostMetrics(db.Stats())
 
 	service := service.New(cfg, log, mailer)
-	models := repository.NewModels(db)
+	cursorKey, err := readCursorKey(cfg.Pastes.CursorKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.Pastes.CursorKey == "" {
+		log.Warn("no cursor key is configured, listing cursors will not survive a restart")
+	}
+
+	models, err := repository.NewModels(db, cfg.Pastes.SlugLength, cursorKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = service.StartJanitor(models.Pastes, models.Tokens); err != nil {
+		log.Fatal(err)
+	}
+
+	handler, err := v1.NewHandler(service, models)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	handler := v1.NewHandler(service, models)
 	srv := server.New(cfg, handler)
 
 	if err = server.Run(srv, service); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// readCursorKey decodes the configured cursor key or generates a random one if none is set.
+// Cursors sealed with a random key are valid only until the restart and on this instance.
+func readCursorKey(s string) ([]byte, error) {
+	if s == "" {
+		key := make([]byte, models.CursorKeySize)
+		_, err := rand.Read(key)
+		return key, err
+	}
+
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) != models.CursorKeySize {
+		return nil, fmt.Errorf("cursor key must be %d hex encoded bytes", models.CursorKeySize)
+	}
+	return key, nil
+}
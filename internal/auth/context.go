@@ -22,3 +22,18 @@ func ContextGetUser(r *http.Request) *models.User {
 	}
 	return user
 }
+
+const tokenContextKey = contextKey("token")
+
+// ContextSetToken stores the plaintext of the token the user was authenticated by.
+func ContextSetToken(r *http.Request, token string) *http.Request {
+	ctx := context.WithValue(r.Context(), tokenContextKey, token)
+	return r.WithContext(ctx)
+}
+
+// ContextGetToken returns the plaintext of the token the user was authenticated by,
+// or an empty string for an anonymous user.
+func ContextGetToken(r *http.Request) string {
+	token, _ := r.Context().Value(tokenContextKey).(string)
+	return token
+}
@@ -27,10 +27,27 @@ type Config struct {
 		MaxIdleConns int    `yaml:"maxIdleConns" envconfig:"PASTE_DB_MAX_IDLE_CONNECTIONS"`
 		MaxIdleTime  string `yaml:"maxIdleTime" envconfig:"PASTE_DB_MAX_IDLE_TIME"`
 	} `yaml:"db"`
+	Pastes struct {
+		SlugLength       int   `yaml:"slugLength" envconfig:"PASTE_SLUG_LENGTH"`
+		MaxJSONSize      int64 `yaml:"maxJSONSize" envconfig:"PASTE_MAX_JSON_SIZE"`
+		MaxTextSize      int64 `yaml:"maxTextSize" envconfig:"PASTE_MAX_TEXT_SIZE"`
+		MaxMultipartSize int64 `yaml:"maxMultipartSize" envconfig:"PASTE_MAX_MULTIPART_SIZE"`
+		RenderCacheSize  int   `yaml:"renderCacheSize" envconfig:"PASTE_RENDER_CACHE_SIZE"`
+		// CursorKey is the hex encoded key listing cursors are sealed with, a random one is used if empty
+		CursorKey string `yaml:"cursorKey" envconfig:"PASTE_CURSOR_KEY"`
+	} `yaml:"pastes"`
+	Janitor struct {
+		Enabled   bool   `yaml:"enabled" envconfig:"PASTE_JANITOR_ENABLED"`
+		Interval  string `yaml:"interval" envconfig:"PASTE_JANITOR_INTERVAL"`
+		BatchSize int    `yaml:"batchSize" envconfig:"PASTE_JANITOR_BATCH_SIZE"`
+	} `yaml:"janitor"`
 	Limiter struct {
 		RPS     float64 `yaml:"rps" envconfig:"API_LIMIT_RPS"`
 		Burst   int     `yaml:"burst" envconfig:"API_LIMIT_BURST"`
 		Enabled bool    `yaml:"enabled" envconfig:"API_LIMIT_ENABLED"`
+		// EmailInterval and EmailBurst limit the emails sent on request to one address
+		EmailInterval string `yaml:"emailInterval" envconfig:"API_LIMIT_EMAIL_INTERVAL"`
+		EmailBurst    int    `yaml:"emailBurst" envconfig:"API_LIMIT_EMAIL_BURST"`
 	} `yaml:"limiter"`
 	SMTP struct {
 		Host     string `yaml:"host" envconfig:"PASTE_SMTP_HOST"`
@@ -91,9 +108,22 @@ func processFlags(cfg *Config) error {
 	flag.IntVar(&cfg.DB.MaxIdleConns, "db-max-idle-conns", cfg.DB.MaxIdleConns, "PostgreSQL max idle connections")
 	flag.StringVar(&cfg.DB.MaxIdleTime, "db-max-idle-time", cfg.DB.MaxIdleTime, "PostgreSQL max connection idle time")
 
+	flag.IntVar(&cfg.Pastes.SlugLength, "paste-slug-length", cfg.Pastes.SlugLength, "Length of generated paste slugs")
+	flag.Int64Var(&cfg.Pastes.MaxJSONSize, "paste-max-json-size", cfg.Pastes.MaxJSONSize, "Maximum size in bytes of JSON paste bodies")
+	flag.Int64Var(&cfg.Pastes.MaxTextSize, "paste-max-text-size", cfg.Pastes.MaxTextSize, "Maximum size in bytes of text/plain paste bodies")
+	flag.Int64Var(&cfg.Pastes.MaxMultipartSize, "paste-max-multipart-size", cfg.Pastes.MaxMultipartSize, "Maximum size in bytes of multipart/form-data paste uploads")
+	flag.IntVar(&cfg.Pastes.RenderCacheSize, "paste-render-cache-size", cfg.Pastes.RenderCacheSize, "Number of rendered Markdown pastes kept in memory")
+	flag.StringVar(&cfg.Pastes.CursorKey, "paste-cursor-key", cfg.Pastes.CursorKey, "Hex encoded 32-byte key listing cursors are sealed with")
+
+	flag.BoolVar(&cfg.Janitor.Enabled, "janitor-enabled", cfg.Janitor.Enabled, "Enable purging of expired pastes and tokens")
+	flag.StringVar(&cfg.Janitor.Interval, "janitor-interval", cfg.Janitor.Interval, "Interval between purges of expired records")
+	flag.IntVar(&cfg.Janitor.BatchSize, "janitor-batch-size", cfg.Janitor.BatchSize, "Maximum number of records deleted by one query")
+
 	flag.Float64Var(&cfg.Limiter.RPS, "limiter-rps", cfg.Limiter.RPS, "Rate limiter maximum requests per second")
 	flag.IntVar(&cfg.Limiter.Burst, "limiter-burst", cfg.Limiter.Burst, "Rate limiter maximum burst")
 	flag.BoolVar(&cfg.Limiter.Enabled, "limiter-enabled", cfg.Limiter.Enabled, "Enable rate limiter")
+	flag.StringVar(&cfg.Limiter.EmailInterval, "limiter-email-interval", cfg.Limiter.EmailInterval, "Interval between emails sent to one address on request")
+	flag.IntVar(&cfg.Limiter.EmailBurst, "limiter-email-burst", cfg.Limiter.EmailBurst, "Maximum burst of emails sent to one address on request")
 
 	flag.StringVar(&cfg.SMTP.Host, "smtp-host", cfg.SMTP.Host, "SMTP host")
 	flag.IntVar(&cfg.SMTP.Port, "smtp-port", cfg.SMTP.Port, "SMTP port")
@@ -24,19 +24,54 @@ func NewRouter(handler *v1.Handler) http.Handler {
 			r.Get("/", handler.ListPastesHandler)
 			r.Post("/", handler.CreatePasteHandler)
 
-			r.Route("/{id}", func(r chi.Router) {
+			r.Route("/{slug}", func(r chi.Router) {
 				r.Get("/", handler.GetPasteHandler)
-				r.Delete("/", handler.RequireAllowedToWriteUser(handler.DeletePasteHandler))
+				r.Get("/raw", handler.GetRawPasteHandler)
+				r.Get("/html", handler.GetHTMLPasteHandler)
+				r.Get("/render", handler.RenderPasteHandler)
+				r.Delete("/", handler.RequirePasteOwner(handler.DeletePasteHandler))
 				r.Patch("/", handler.RequireAllowedToWriteUser(handler.UpdatePasteHandler))
+				r.Put("/owner", handler.RequirePasteOwner(handler.TransferPasteHandler))
+
+				r.Get("/collaborators", handler.RequireAllowedToWriteUser(handler.ListCollaboratorsHandler))
+				r.Post("/collaborators", handler.RequirePasteOwner(handler.AddCollaboratorHandler))
+				r.Delete("/collaborators", handler.RequirePasteOwner(handler.RemoveCollaboratorHandler))
+
+				r.Get("/revisions", handler.RequireAllowedToWriteUser(handler.ListRevisionsHandler))
+				r.Get("/revisions/{version}", handler.RequireAllowedToWriteUser(handler.GetRevisionHandler))
+				r.Post("/revisions/{version}/restore", handler.RequireAllowedToWriteUser(handler.RestoreRevisionHandler))
+				r.Get("/diff", handler.RequireAllowedToWriteUser(handler.DiffRevisionsHandler))
 			})
 		})
 
+		r.Route("/categories", func(r chi.Router) {
+			r.Get("/", handler.ListCategoriesHandler)
+			r.Post("/", handler.RequireAdminUser(handler.CreateCategoryHandler))
+			r.Patch("/{id}", handler.RequireAdminUser(handler.UpdateCategoryHandler))
+			r.Delete("/{id}", handler.RequireAdminUser(handler.DeleteCategoryHandler))
+		})
+
+		r.Get("/tags", handler.ListTagsHandler)
+
 		r.Route("/users", func(r chi.Router) {
 			r.Post("/", handler.RegisterUserHandler)
 			r.Put("/activated", handler.ActivateUserHandler)
+			r.Put("/email", handler.ConfirmEmailHandler)
+			r.Put("/password", handler.ResetPasswordHandler)
+			r.Get("/me", handler.RequireAuthenticatedUser(handler.GetCurrentUserHandler))
+			r.Patch("/me", handler.RequireActivatedUser(handler.UpdateCurrentUserHandler))
+			r.Delete("/me", handler.RequireAuthenticatedUser(handler.DeleteCurrentUserHandler))
+			r.Put("/me/password", handler.RequireAuthenticatedUser(handler.ChangePasswordHandler))
+			r.Get("/me/pastes", handler.RequireActivatedUser(handler.ListMyPastesHandler))
+			r.Get("/me/sessions", handler.RequireAuthenticatedUser(handler.ListSessionsHandler))
+			r.Delete("/me/sessions/{id}", handler.RequireAuthenticatedUser(handler.DeleteSessionHandler))
+			r.Get("/{login}/pastes", handler.ListUserPastesHandler)
 		})
 
 		r.Post("/tokens/authentication", handler.CreateAuthenticationTokenHandler)
+		r.Delete("/tokens/authentication", handler.RequireAuthenticatedUser(handler.DeleteAuthenticationTokenHandler))
+		r.Post("/tokens/password-reset", handler.CreatePasswordResetTokenHandler)
+		r.Post("/tokens/activation", handler.CreateActivationTokenHandler)
 	})
 
 	return handler.Metrics(handler.RecoverPanic(handler.EnableCORS(handler.RateLimit(handler.Authenticate(handler.DebugRequest(r))))))
@@ -0,0 +1,206 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"pasteAPI/internal/repository"
+	"pasteAPI/internal/repository/models"
+	"pasteAPI/pkg/helpers"
+	"pasteAPI/pkg/validator"
+)
+
+type ListCategoriesOutput struct {
+	Categories []*models.Category `json:"categories"`
+}
+
+// ListCategoriesHandler retrieves all categories
+//
+// @Summary      List categories
+// @Description  Retrieves all categories pastes can be put in.
+// @Tags         categories
+// @Produce      json
+// @Success      200  {object}  ListCategoriesOutput  "Successfully retrieved categories"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/categories [get]
+func (h *Handler) ListCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.models.Categories.GetAll()
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"categories": categories}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+type CategoryInput struct {
+	Name string `json:"name"`
+}
+
+type CategoryResp struct {
+	R *models.Category `json:"category"`
+}
+
+// CreateCategoryHandler creates a new category
+//
+// @Summary      Create a category
+// @Description  Creates a new category. Available to administrators only.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        body  body     CategoryInput  true  "Category input"
+// @Security Bearer
+// @Success      201  {object}  CategoryResp  "Successfully created category"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      403  {object}  ErrorResponse "User is not an administrator"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/categories [post]
+func (h *Handler) CreateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	var in CategoryInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	category := &models.Category{Name: in.Name}
+
+	v := validator.New()
+	if models.ValidateCategory(v, category); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = h.models.Categories.Insert(category)
+	if err != nil {
+		h.categoryErrorResponse(w, r, v, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("api/v1/categories/%d", category.ID))
+
+	err = helpers.WriteJSON(w, http.StatusCreated, helpers.Envelope{"category": category}, headers)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// UpdateCategoryHandler renames a category
+//
+// @Summary      Rename a category
+// @Description  Changes the name of a category. Available to administrators only.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id    path     int  true  "Category ID"
+// @Param        body  body     CategoryInput  true  "Category input"
+// @Security Bearer
+// @Success      200  {object}  CategoryResp  "Successfully updated category"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      403  {object}  ErrorResponse "User is not an administrator"
+// @Failure      404  {object}  ErrorResponse "Category not found"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/categories/{id} [patch]
+func (h *Handler) UpdateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.readCategoryID(w, r)
+	if !ok {
+		return
+	}
+
+	var in CategoryInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	category := &models.Category{ID: id, Name: in.Name}
+
+	v := validator.New()
+	if models.ValidateCategory(v, category); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = h.models.Categories.Update(category)
+	if err != nil {
+		h.categoryErrorResponse(w, r, v, err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"category": category}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// DeleteCategoryHandler deletes a category
+//
+// @Summary      Delete a category
+// @Description  Deletes a category which has no pastes. Available to administrators only.
+// @Tags         categories
+// @Produce      json
+// @Param        id    path     int  true  "Category ID"
+// @Security Bearer
+// @Success      204  "Successfully deleted category"
+// @Failure      403  {object}  ErrorResponse "User is not an administrator"
+// @Failure      404  {object}  ErrorResponse "Category not found"
+// @Failure      409  {object}  ErrorResponse "Category has pastes"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/categories/{id} [delete]
+func (h *Handler) DeleteCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.readCategoryID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.models.Categories.Delete(id)
+	if err != nil {
+		h.categoryErrorResponse(w, r, validator.New(), err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusNoContent, nil, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+func (h *Handler) readCategoryID(w http.ResponseWriter, r *http.Request) (uint8, bool) {
+	id, err := helpers.ReadIDParam(r)
+	if err != nil || id < 1 || id > math.MaxUint8 {
+		h.NotFoundResponse(w, r)
+		return 0, false
+	}
+	return uint8(id), true
+}
+
+func (h *Handler) categoryErrorResponse(w http.ResponseWriter, r *http.Request, v *validator.Validator, err error) {
+	switch {
+	case errors.Is(err, repository.ErrRecordNotFound):
+		h.NotFoundResponse(w, r)
+	case errors.Is(err, repository.ErrDuplicateCategory):
+		v.AddError("name", "a category with this name already exists")
+		h.FailedValidationResponse(w, r, v.Errors)
+	case errors.Is(err, repository.ErrTooManyCategories):
+		v.AddError("category", "the limit of 255 categories is reached")
+		h.FailedValidationResponse(w, r, v.Errors)
+	case errors.Is(err, repository.ErrCategoryInUse):
+		h.CategoryInUseResponse(w, r)
+	default:
+		h.ServerErrorResponse(w, r, err)
+	}
+}
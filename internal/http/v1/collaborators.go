@@ -0,0 +1,294 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"pasteAPI/internal/auth"
+	"pasteAPI/internal/repository"
+	"pasteAPI/internal/repository/models"
+	"pasteAPI/pkg/helpers"
+	"pasteAPI/pkg/validator"
+)
+
+type CollaboratorInput struct {
+	Login string `json:"login,omitempty"`
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role,omitempty"`
+}
+
+type RemoveCollaboratorInput struct {
+	Login string `json:"login,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type CollaboratorResp struct {
+	C *models.Collaborator `json:"collaborator"`
+}
+
+type ListCollaboratorsOutput struct {
+	C []*models.Collaborator `json:"collaborators"`
+}
+
+// ListCollaboratorsHandler retrieves users who were granted access to the paste
+//
+// @Summary      List collaborators
+// @Description  Retrieves users who were granted access to the paste and their roles.
+// @Tags         collaborators
+// @Produce      json
+// @Param        slug   path     string   true   "Paste slug"
+// @Security Bearer
+// @Success      200  {object}  ListCollaboratorsOutput  "Successfully retrieved collaborators"
+// @Failure      403  {object}  ErrorResponse "User is not allowed to edit this paste"
+// @Failure      404  {object}  ErrorResponse "Paste not found"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/collaborators [get]
+func (h *Handler) ListCollaboratorsHandler(w http.ResponseWriter, r *http.Request) {
+	slug, err := helpers.ReadSlugParam(r)
+	if err != nil {
+		h.NotFoundResponse(w, r)
+		return
+	}
+
+	paste, err := h.models.Pastes.Get(slug)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	collaborators, err := h.models.Permissions.GetCollaborators(paste.Id)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"collaborators": collaborators}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// AddCollaboratorHandler grants a user access to the paste
+//
+// @Summary      Add a collaborator
+// @Description  Grants a user found by login or email read or write access to the paste and notifies them by email.
+// @Description  Granting access to an existing collaborator changes their role. The user account must be activated.
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Param        slug   path     string   true   "Paste slug"
+// @Param        body  body     CollaboratorInput  true  "Collaborator and role, write by default"
+// @Security Bearer
+// @Success      200  {object}  CollaboratorResp  "Successfully changed the role"
+// @Success      201  {object}  CollaboratorResp  "Successfully added collaborator"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      403  {object}  ErrorResponse "User is not the owner of this paste"
+// @Failure      404  {object}  ErrorResponse "Paste not found"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/collaborators [post]
+func (h *Handler) AddCollaboratorHandler(w http.ResponseWriter, r *http.Request) {
+	slug, err := helpers.ReadSlugParam(r)
+	if err != nil {
+		h.NotFoundResponse(w, r)
+		return
+	}
+
+	var in CollaboratorInput
+
+	err = helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	if in.Role == "" {
+		in.Role = models.RoleWrite
+	}
+
+	v := validator.New()
+	models.ValidateRole(v, in.Role)
+	validateUserReference(v, in.Login, in.Email)
+	if !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	paste, err := h.models.Pastes.Get(slug)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	user, ok := h.findReferencedUser(w, r, v, in.Login, in.Email)
+	if !ok {
+		return
+	}
+
+	owner := auth.ContextGetUser(r)
+	v.Check(user.Activated, "user", "user account must be activated")
+	if v.Check(user.ID != owner.ID, "user", "the owner can not be a collaborator"); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	added, err := h.models.Permissions.SetPermission(user.ID, paste.Id, in.Role)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if added {
+		status = http.StatusCreated
+
+		h.service.Background(func() {
+			tmplData := map[string]interface{}{
+				"Login": user.Login,
+				"Owner": owner.Login,
+				"Role":  in.Role,
+				"Title": paste.Title,
+				"Slug":  paste.Slug,
+			}
+			err := h.service.Mailer.SendEmail(user.Email, "collaborator.tmpl", tmplData)
+			if err != nil {
+				h.service.Logger.Error(err)
+			}
+		})
+	}
+
+	collaborator := &models.Collaborator{
+		UserID: user.ID,
+		Login:  user.Login,
+		Role:   in.Role,
+	}
+
+	err = helpers.WriteJSON(w, status, helpers.Envelope{"collaborator": collaborator}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// RemoveCollaboratorHandler revokes access to the paste
+//
+// @Summary      Remove a collaborator
+// @Description  Revokes access to the paste from a user found by login or email.
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Param        slug   path     string   true   "Paste slug"
+// @Param        body  body     RemoveCollaboratorInput  true  "Collaborator"
+// @Security Bearer
+// @Success      204  "Successfully removed collaborator"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      403  {object}  ErrorResponse "User is not the owner of this paste"
+// @Failure      404  {object}  ErrorResponse "Paste or collaborator not found"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/collaborators [delete]
+func (h *Handler) RemoveCollaboratorHandler(w http.ResponseWriter, r *http.Request) {
+	slug, err := helpers.ReadSlugParam(r)
+	if err != nil {
+		h.NotFoundResponse(w, r)
+		return
+	}
+
+	var in RemoveCollaboratorInput
+
+	err = helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if validateUserReference(v, in.Login, in.Email); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	paste, err := h.models.Pastes.Get(slug)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	user, ok := h.findReferencedUser(w, r, v, in.Login, in.Email)
+	if !ok {
+		return
+	}
+
+	err = h.models.Permissions.DeletePermission(user.ID, paste.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusNoContent, nil, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// validateUserReference checks that exactly one of login and email identifies the user.
+func validateUserReference(v *validator.Validator, login, email string) {
+	switch {
+	case login != "" && email != "":
+		v.AddError("user", "provide either login or email, not both")
+	case login != "":
+		models.ValidateLogin(v, login)
+	case email != "":
+		models.ValidateEmail(v, email)
+	default:
+		v.AddError("user", "login or email must be provided")
+	}
+}
+
+// findReferencedUser looks the user up by login or email and sends the error response if it fails.
+func (h *Handler) findReferencedUser(w http.ResponseWriter, r *http.Request, v *validator.Validator, login, email string) (*models.User, bool) {
+	var (
+		user *models.User
+		err  error
+	)
+
+	if login != "" {
+		user, err = h.models.Users.GetByLogin(login)
+	} else {
+		user, err = h.models.Users.GetByEmail(email)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			v.AddError("user", "no such user")
+			h.FailedValidationResponse(w, r, v.Errors)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return nil, false
+	}
+
+	return user, true
+}
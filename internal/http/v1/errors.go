@@ -57,6 +57,21 @@ func (h *Handler) EditConflictResponse(w http.ResponseWriter, r *http.Request) {
 	h.ErrorResponse(w, r, http.StatusConflict, message)
 }
 
+func (h *Handler) UnsupportedMediaTypeResponse(w http.ResponseWriter, r *http.Request) {
+	message := fmt.Sprintf("the %s content type is not supported for this resource", r.Header.Get("Content-Type"))
+	h.ErrorResponse(w, r, http.StatusUnsupportedMediaType, message)
+}
+
+func (h *Handler) CategoryInUseResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the category has pastes, move them to another category first"
+	h.ErrorResponse(w, r, http.StatusConflict, message)
+}
+
+func (h *Handler) PreconditionFailedResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the record has been changed since the version you have, fetch it again and retry"
+	h.ErrorResponse(w, r, http.StatusPreconditionFailed, message)
+}
+
 func (h *Handler) RateLimitExceededResponse(w http.ResponseWriter, r *http.Request) {
 	message := "rate limit exceeded"
 	h.ErrorResponse(w, r, http.StatusTooManyRequests, message)
@@ -84,3 +99,18 @@ func (h *Handler) ForbiddenResponse(w http.ResponseWriter, r *http.Request) {
 	message := "you are not allowed to access this resource"
 	h.ErrorResponse(w, r, http.StatusForbidden, message)
 }
+
+func (h *Handler) InvalidCurrentPasswordResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the current password does not match"
+	h.ErrorResponse(w, r, http.StatusForbidden, message)
+}
+
+func (h *Handler) PastePasswordRequiredResponse(w http.ResponseWriter, r *http.Request) {
+	message := "this paste is protected, provide its password in the X-Paste-Password header"
+	h.ErrorResponse(w, r, http.StatusUnauthorized, message)
+}
+
+func (h *Handler) InvalidPastePasswordResponse(w http.ResponseWriter, r *http.Request) {
+	message := "invalid paste password"
+	h.ErrorResponse(w, r, http.StatusForbidden, message)
+}
@@ -1,18 +1,43 @@
 package v1
 
 import (
+	"errors"
 	"pasteAPI/internal/repository"
 	"pasteAPI/internal/service"
+	"pasteAPI/pkg/markdown"
+	"time"
 )
 
 type Handler struct {
-	service *service.Service
-	models  *repository.Models
+	service          *service.Service
+	models           *repository.Models
+	rendered         *markdown.Cache
+	activationEmails *keyLimiter
+	resetEmails      *keyLimiter
+	sessions         *keyLimiter
 }
 
-func NewHandler(service *service.Service, models *repository.Models) *Handler {
-	return &Handler{
-		service: service,
-		models:  models,
+func NewHandler(service *service.Service, models *repository.Models) (*Handler, error) {
+	emailInterval, err := time.ParseDuration(service.Config.Limiter.EmailInterval)
+	if err != nil {
+		return nil, err
+	}
+	if service.Config.Limiter.EmailBurst <= 0 {
+		return nil, errors.New("email burst must be greater than zero")
+	}
+
+	limits := service.Config.Pastes
+	if limits.MaxJSONSize <= 0 || limits.MaxTextSize <= 0 || limits.MaxMultipartSize <= 0 {
+		return nil, errors.New("maximum paste body sizes must be greater than zero")
 	}
+
+	return &Handler{
+		service:          service,
+		models:           models,
+		rendered:         markdown.NewCache(service.Config.Pastes.RenderCacheSize),
+		activationEmails: newKeyLimiter(emailInterval, service.Config.Limiter.EmailBurst),
+		resetEmails:      newKeyLimiter(emailInterval, service.Config.Limiter.EmailBurst),
+		// the last use of a session is recorded at most once a minute
+		sessions: newKeyLimiter(time.Minute, 1),
+	}, nil
 }
@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"golang.org/x/time/rate"
+	"sync"
+	"time"
+)
+
+// keyLimiter allows events at a limited rate for each key, such as an email address.
+// Limiters which have refilled are forgotten, as a new one behaves the same.
+type keyLimiter struct {
+	mu       sync.Mutex
+	limit    rate.Limit
+	burst    int
+	limiters map[string]*rate.Limiter
+	sweptAt  time.Time
+}
+
+func newKeyLimiter(interval time.Duration, burst int) *keyLimiter {
+	return &keyLimiter{
+		limit:    rate.Every(interval),
+		burst:    burst,
+		limiters: make(map[string]*rate.Limiter),
+		sweptAt:  time.Now(),
+	}
+}
+
+// Allow reports whether an event for the key may happen now.
+func (l *keyLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(l.sweptAt) > time.Minute {
+		for k, limiter := range l.limiters {
+			if limiter.TokensAt(now) >= float64(l.burst) {
+				delete(l.limiters, k)
+			}
+		}
+		l.sweptAt = now
+	}
+
+	limiter, found := l.limiters[key]
+	if !found {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[key] = limiter
+	}
+
+	return limiter.AllowN(now, 1)
+}
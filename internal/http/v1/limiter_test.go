@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyLimiterAllow(t *testing.T) {
+	tests := []struct {
+		name  string
+		burst int
+		keys  []string
+		want  []bool
+	}{
+		{name: "single event", burst: 1, keys: []string{"a", "a"}, want: []bool{true, false}},
+		{name: "burst", burst: 3, keys: []string{"a", "a", "a", "a"}, want: []bool{true, true, true, false}},
+		{name: "keys are independent", burst: 1, keys: []string{"a", "b", "a", "b", "c"}, want: []bool{true, true, false, false, true}},
+		{name: "keys are case sensitive", burst: 1, keys: []string{"a", "A"}, want: []bool{true, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newKeyLimiter(time.Hour, tt.burst)
+			for i, key := range tt.keys {
+				if got := l.Allow(key); got != tt.want[i] {
+					t.Errorf("event %d: Allow(%q) = %v, want %v", i, key, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKeyLimiterRefill(t *testing.T) {
+	l := newKeyLimiter(200*time.Millisecond, 1)
+
+	if !l.Allow("a") {
+		t.Fatal("first Allow() = false")
+	}
+	if l.Allow("a") {
+		t.Fatal("second Allow() = true before the interval")
+	}
+
+	time.Sleep(250 * time.Millisecond)
+
+	if !l.Allow("a") {
+		t.Error("Allow() = false after the interval")
+	}
+}
+
+func TestKeyLimiterSweep(t *testing.T) {
+	l := newKeyLimiter(time.Millisecond, 1)
+
+	l.Allow("refilled")
+	time.Sleep(5 * time.Millisecond)
+
+	slow := newKeyLimiter(time.Hour, 1)
+	slow.Allow("waiting")
+
+	// pretend the last sweep was long ago, so the next event sweeps
+	l.sweptAt = time.Now().Add(-2 * time.Minute)
+	slow.sweptAt = l.sweptAt
+
+	l.Allow("other")
+	if _, found := l.limiters["refilled"]; found {
+		t.Error("refilled limiter was not swept")
+	}
+	if l.sweptAt.Before(time.Now().Add(-time.Minute)) {
+		t.Error("sweep time was not updated")
+	}
+
+	slow.Allow("other")
+	if _, found := slow.limiters["waiting"]; !found {
+		t.Error("limiter which has not refilled was swept")
+	}
+	if slow.Allow("waiting") {
+		t.Error("Allow() = true for a key whose limiter was kept")
+	}
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"crypto/sha256"
 	"errors"
 	"expvar"
 	"fmt"
@@ -128,7 +129,19 @@ func (h *Handler) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
+		// the limiter is keyed by the hash stored in the database, so no token is kept in memory
+		tokenHash := sha256.Sum256([]byte(token))
+		if h.sessions.Allow(string(tokenHash[:])) {
+			h.service.Background(func() {
+				err := h.models.Tokens.Touch(token)
+				if err != nil {
+					h.service.Logger.Error(err)
+				}
+			})
+		}
+
 		r = auth.ContextSetUser(r, user)
+		r = auth.ContextSetToken(r, token)
 		next.ServeHTTP(w, r)
 	})
 }
@@ -156,16 +169,28 @@ func (h *Handler) RequireActivatedUser(next http.HandlerFunc) http.HandlerFunc {
 	return h.RequireAuthenticatedUser(fn)
 }
 
+func (h *Handler) RequireAdminUser(next http.HandlerFunc) http.HandlerFunc {
+	fn := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := auth.ContextGetUser(r)
+		if !user.Admin {
+			h.ForbiddenResponse(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+	return h.RequireActivatedUser(fn)
+}
+
 func (h *Handler) RequireAllowedToWriteUser(next http.HandlerFunc) http.HandlerFunc {
 	fn := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user := auth.ContextGetUser(r)
-		pasteId, err := helpers.ReadIDParam(r)
+		slug, err := helpers.ReadSlugParam(r)
 		if err != nil {
 			h.BadRequestResponse(w, r, err)
 			return
 		}
 
-		allowed, err := h.models.Permissions.GetWritePermission(user.ID, uint16(pasteId))
+		allowed, err := h.models.Permissions.GetWritePermission(user.ID, slug)
 		if err != nil {
 			h.ServerErrorResponse(w, r, err)
 			return
@@ -180,6 +205,30 @@ func (h *Handler) RequireAllowedToWriteUser(next http.HandlerFunc) http.HandlerF
 	return h.RequireActivatedUser(fn)
 }
 
+func (h *Handler) RequirePasteOwner(next http.HandlerFunc) http.HandlerFunc {
+	fn := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := auth.ContextGetUser(r)
+		slug, err := helpers.ReadSlugParam(r)
+		if err != nil {
+			h.BadRequestResponse(w, r, err)
+			return
+		}
+
+		owner, err := h.models.Permissions.IsOwner(user.ID, slug)
+		if err != nil {
+			h.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		if !owner {
+			h.ForbiddenResponse(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+	return h.RequireActivatedUser(fn)
+}
+
 func (h *Handler) EnableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Vary", "Origin")
@@ -189,9 +238,10 @@ func (h *Handler) EnableCORS(next http.Handler) http.Handler {
 			for i := range h.service.Config.CORS.TrustedOrigins {
 				if origin == h.service.Config.CORS.TrustedOrigins[i] {
 					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified")
 					if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
 						w.Header().Set("Access-Control-Allow-Methods", "OPTIONS, PUT, PATCH, DELETE")
-						w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+						w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, If-Match, If-None-Match, If-Modified-Since, X-Paste-Password")
 
 						w.WriteHeader(http.StatusOK)
 						return
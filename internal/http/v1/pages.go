@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"pasteAPI/internal/repository/models"
+	"pasteAPI/pkg/helpers"
+	"pasteAPI/pkg/highlight"
+	"pasteAPI/pkg/htmlpage"
+	"pasteAPI/pkg/markdown"
+	"pasteAPI/pkg/validator"
+)
+
+// GetHTMLPasteHandler renders a paste as a highlighted HTML page
+//
+// @Summary      Retrieve a paste as HTML
+// @Description  Renders a standalone HTML page with the paste text highlighted according to its language.
+// @Description  Lines are numbered and can be linked with anchors like #L10 or #L10-L20.
+// @Description  Expiry, visibility, password and view limits are the same as for the JSON representation.
+// @Tags         pastes
+// @Produce      html
+// @Param        slug   path   string   true       "Paste slug"
+// @Param        theme   query   string   false       "Color theme, light or dark"
+// @Param        X-Paste-Password   header   string   false       "Password of a protected paste"
+// @Success      200  {string}  string  "HTML page"
+// @Success      304  "Paste has not changed"
+// @Failure      401  {object}  ErrorResponse "Paste password required"
+// @Failure      403  {object}  ErrorResponse "Invalid paste password"
+// @Failure      404  {object}  ErrorResponse "Paste not found"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/html [get]
+func (h *Handler) GetHTMLPasteHandler(w http.ResponseWriter, r *http.Request) {
+	theme, ok := h.readTheme(w, r)
+	if !ok {
+		return
+	}
+
+	paste, headers, ok := h.readPaste(w, r)
+	if !ok {
+		return
+	}
+
+	nonce, err := htmlpage.NewNonce()
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	page := htmlpage.Code{
+		Page:  pastePage(paste, theme, nonce),
+		Lines: highlight.Lines(paste.Language, paste.Text),
+	}
+
+	h.writeHTML(w, r, "code", page, htmlpage.ContentSecurityPolicy(nonce, false), headers)
+}
+
+// RenderPasteHandler renders a Markdown paste as an HTML page
+//
+// @Summary      Render a paste
+// @Description  Converts a Markdown paste to HTML sanitized against an allowlist, so embedded scripts
+// @Description  and frames are removed, and returns it as a standalone page. Plain pastes are shown
+// @Description  the same way as by the HTML endpoint. The rendered HTML is cached until the paste changes.
+// @Tags         pastes
+// @Produce      html
+// @Param        slug   path   string   true       "Paste slug"
+// @Param        theme   query   string   false       "Color theme, light or dark"
+// @Param        X-Paste-Password   header   string   false       "Password of a protected paste"
+// @Success      200  {string}  string  "HTML page"
+// @Success      304  "Paste has not changed"
+// @Failure      401  {object}  ErrorResponse "Paste password required"
+// @Failure      403  {object}  ErrorResponse "Invalid paste password"
+// @Failure      404  {object}  ErrorResponse "Paste not found"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/render [get]
+func (h *Handler) RenderPasteHandler(w http.ResponseWriter, r *http.Request) {
+	theme, ok := h.readTheme(w, r)
+	if !ok {
+		return
+	}
+
+	paste, headers, ok := h.readPaste(w, r)
+	if !ok {
+		return
+	}
+
+	nonce, err := htmlpage.NewNonce()
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	page := pastePage(paste, theme, nonce)
+
+	if paste.Format != models.FormatMarkdown {
+		code := htmlpage.Code{Page: page, Lines: highlight.Lines(paste.Language, paste.Text)}
+		h.writeHTML(w, r, "code", code, htmlpage.ContentSecurityPolicy(nonce, false), headers)
+		return
+	}
+
+	body, ok := h.rendered.Get(paste.Id, paste.Version)
+	if !ok {
+		body, err = markdown.Render(paste.Text)
+		if err != nil {
+			h.ServerErrorResponse(w, r, err)
+			return
+		}
+		h.rendered.Put(paste.Id, paste.Version, body)
+	}
+
+	document := htmlpage.Document{Page: page, Body: body}
+	h.writeHTML(w, r, "document", document, htmlpage.ContentSecurityPolicy(nonce, true), headers)
+}
+
+func pastePage(paste *models.Paste, theme, nonce string) htmlpage.Page {
+	page := htmlpage.Page{
+		Title:     paste.Title,
+		Info:      paste.Language,
+		Theme:     theme,
+		Nonce:     nonce,
+		CreatedAt: paste.CreatedAt,
+		ExpiresAt: paste.ExpiresAt,
+	}
+
+	// the raw text of pastes with limited views can not be fetched once more
+	if paste.MaxViews == nil {
+		page.RawURL = fmt.Sprintf("/api/v1/pastes/%s/raw", paste.Slug)
+	}
+
+	return page
+}
+
+// readTheme reads the theme query parameter, responding with 422 if it is unknown.
+func (h *Handler) readTheme(w http.ResponseWriter, r *http.Request) (string, bool) {
+	theme := helpers.ReadString(r.URL.Query(), "theme", htmlpage.Themes[0])
+
+	v := validator.New()
+	if v.Check(validator.In(theme, htmlpage.Themes...), "theme", "unknown theme"); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return "", false
+	}
+
+	return theme, true
+}
+
+// writeHTML renders the page and sends it with the headers and the content security policy.
+func (h *Handler) writeHTML(w http.ResponseWriter, r *http.Request, name string, page interface{}, policy string, headers http.Header) {
+	var buf bytes.Buffer
+
+	err := htmlpage.Render(&buf, name, page)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	for key, value := range headers {
+		w.Header()[key] = value
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", policy)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
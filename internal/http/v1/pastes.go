@@ -3,20 +3,27 @@ package v1
 import (
 	"errors"
 	"fmt"
+	"math"
+	"mime"
 	"net/http"
+	"net/url"
 	"pasteAPI/internal/auth"
 	"pasteAPI/internal/repository"
 	"pasteAPI/internal/repository/models"
 	"pasteAPI/pkg/helpers"
+	"pasteAPI/pkg/langdetect"
+	"pasteAPI/pkg/textlang"
 	"pasteAPI/pkg/validator"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type SearchSettings struct {
-	Title    string
-	Category uint8
-	Filters  models.Filters
+	Search  models.PasteSearch
+	Filters models.Filters
 }
 
 type ListPastesOutput struct {
@@ -24,48 +31,132 @@ type ListPastesOutput struct {
 	Metadata *models.Metadata `json:"metadata"`
 }
 
-// ListPastesHandler retrieves a paste by its ID
+// ListPastesHandler retrieves public pastes
 //
-// @Summary      Retrieve a paste
-// @Description  Retrieves a paste from the database by its ID.
+// @Summary      List pastes
+// @Description  Retrieves public pastes from the database. Unlisted and private pastes are left out.
+// @Description  The q parameter searches both titles and texts in the natural language of each paste or only in the given one,
+// @Description  the results are sorted by relevance
+// @Description  unless another sort is given and carry snippets of the matching text with the matches wrapped in mark tags.
+// @Description  Text of protected pastes and of pastes with limited views is not searched.
+// @Description  Besides page numbers, the listing can be paged with the cursors from next_cursor and prev_cursor
+// @Description  passed in the after and before parameters, an empty after starts from the first page.
+// @Description  Cursor pages are not shifted by new pastes. The total and the last page are returned only if count is set.
 // @Tags         pastes
 // @Produce      json
+// @Param        q         query    string  false  "Search query, e.g. \"connection pool\" -mysql"
 // @Param        title     query    string  false  "Title of the paste"
 // @Param        category  query    int     false  "Category ID of the paste"
-// @Param        sort      query    string  false  "Sort order, e.g., -created_at"
+// @Param        language  query    string  false  "Language of the paste, e.g. go"
+// @Param        natural_language  query  string  false  "Natural language of the paste and the search query, e.g. english"
+// @Param        tags      query    string  false  "Comma-separated tags, e.g. go,sql"
+// @Param        tags_match  query  string  false  "Whether pastes must have any or all of the tags, any by default"
+// @Param        sort      query    string  false  "Sort order, e.g., -created_at, or -rank with q"
 // @Param        page      query    int     false  "Page number for pagination"
 // @Param        pageSize  query    int     false  "Number of items per page"
+// @Param        after     query    string  false  "Cursor of the page to start after"
+// @Param        before    query    string  false  "Cursor of the page to end before"
+// @Param        count     query    bool    false  "Count the total of the matching pastes, false by default"
+// @Param        If-None-Match   header   string   false   "ETag of the cached listing"
 // @Success      200  {object}  ListPastesOutput  "Successfully retrieved paste"
+// @Header       200  {string}  ETag  "Entity tag of the listing"
+// @Success      304  "Listing has not changed"
 // @Failure      422  {object}  ErrorResponse "Unprocessing data"
 // @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
 // @Failure      500  {object}  ErrorResponse "Internal server error"
 // @Router       /api/v1/pastes/ [get]
 func (h *Handler) ListPastesHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	in := h.readSearchSettings(r.URL.Query(), v)
+	if models.ValidateFilters(v, in.Filters); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	// listings change with every new paste, so caches have to revalidate them each time
+	h.writePasteList(w, r, in, "public, no-cache")
+}
+
+// readSearchSettings reads the search, the sort and the paging of a paste listing from the query string.
+func (h *Handler) readSearchSettings(qs url.Values, v *validator.Validator) SearchSettings {
 	var in SearchSettings
 
-	qs := r.URL.Query()
-	in.Title = helpers.ReadString(qs, "title", "")
-	in.Filters.Sort = helpers.ReadString(qs, "sort", "-created_at")
+	in.Search.Query = strings.TrimSpace(helpers.ReadString(qs, "q", ""))
+	in.Search.Title = helpers.ReadString(qs, "title", "")
+	in.Search.Language = helpers.ReadString(qs, "language", "")
+	in.Search.NaturalLanguage = helpers.ReadString(qs, "natural_language", "")
+	// a tag repeated in the filter would never be matched by all of a paste's distinct tags
+	in.Search.Tags = slices.Compact(models.NormalizeTags(helpers.ReadCSV(qs, "tags", nil)))
+	in.Search.TagsMatch = helpers.ReadString(qs, "tags_match", models.TagsMatchAny)
+
+	defaultSort := "-created_at"
+	if in.Search.Query != "" {
+		defaultSort = "-rank"
+	}
+	in.Filters.Sort = helpers.ReadString(qs, "sort", defaultSort)
 
-	v := validator.New()
-	in.Category = uint8(helpers.ReadInt(qs, "category", 0, v))
+	in.Search.Category = uint8(helpers.ReadInt(qs, "category", 0, v))
 	in.Filters.Page = uint32(helpers.ReadInt(qs, "page", 1, v))
 	in.Filters.PageSize = uint32(helpers.ReadInt(qs, "pageSize", 5, v))
-	in.Filters.SortSafelist = []string{"id", "-id", "title", "-title", "created_at", "-created_at", "expires_at", "-expires_at"}
+	in.Filters.SortSafelist = []string{"id", "-id", "title", "-title", "created_at", "-created_at", "expires_at", "-expires_at", "rank", "-rank"}
 
-	models.ValidateFilters(v, in.Filters)
-	if !v.Valid() {
-		h.FailedValidationResponse(w, r, v.Errors)
-		return
+	if qs.Has("after") || qs.Has("before") {
+		in.Filters.Keyset = true
+		v.Check(!(qs.Has("after") && qs.Has("before")), "cursor", "after and before can not be used together")
+		v.Check(!qs.Has("page"), "page", "can not be used with a cursor")
+
+		cursor := qs.Get("after")
+		if qs.Has("before") {
+			cursor, in.Filters.Backward = qs.Get("before"), true
+		}
+
+		var err error
+		if cursor != "" {
+			in.Filters.Cursor, err = h.models.Cursors.Decode(cursor)
+			if err != nil {
+				v.AddError("cursor", err.Error())
+			} else if !qs.Has("sort") {
+				// the cursor remembers the sort of the listing it came from
+				in.Filters.Sort = in.Filters.Cursor.Sort
+			}
+		}
+		v.Check(!in.Filters.Backward || cursor != "", "before", "must be provided")
 	}
+	// counting scans every matching paste, so the total is computed only when asked for
+	in.Filters.Count = helpers.ReadBool(qs, "count", false, v)
 
-	pastes, metadata, err := h.models.Pastes.ReadAll(in.Title, in.Category, in.Filters)
+	if in.Search.Language != "" {
+		v.Check(langdetect.IsKnown(in.Search.Language), "language", "unknown language")
+	}
+	if in.Search.NaturalLanguage != "" {
+		v.Check(textlang.IsKnown(in.Search.NaturalLanguage), "natural_language", "unknown natural language")
+	}
+	v.Check(len(in.Search.Query) <= 256, "q", "must not be more than 256 bytes long")
+	if in.Search.Query == "" {
+		v.Check(!strings.HasSuffix(in.Filters.Sort, "rank"), "sort", "rank sort requires the q parameter")
+	}
+	v.Check(validator.In(in.Search.TagsMatch, models.TagsMatchAny, models.TagsMatchAll), "tags_match", "must be any or all")
+	for _, tag := range in.Search.Tags {
+		v.Check(validator.Matches(tag, models.TagRX), "tags", "must contain valid tags only")
+	}
+	v.Check(len(in.Search.Tags) <= models.MaxTags, "tags", "must not contain more than 10 tags")
+
+	return in
+}
+
+// writePasteList writes the listing with an ETag, so unchanged listings are answered with 304.
+func (h *Handler) writePasteList(w http.ResponseWriter, r *http.Request, in SearchSettings, cacheControl string) {
+	pastes, metadata, err := h.models.Pastes.ReadAll(in.Search, in.Filters)
 	if err != nil {
 		h.ServerErrorResponse(w, r, err)
 		return
 	}
-	// SendEmail a JSON response containing the movie repository.
-	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"pastes": pastes, "metadata": metadata}, nil)
+
+	headers := make(http.Header)
+	headers.Set("Cache-Control", cacheControl)
+
+	err = helpers.WriteJSONWithETag(w, r, http.StatusOK, helpers.Envelope{"pastes": pastes, "metadata": metadata}, headers)
 	if err != nil {
 		h.ServerErrorResponse(w, r, err)
 	}
@@ -75,26 +166,107 @@ type PasteResp struct {
 	R *models.Paste `json:"paste"`
 }
 
-// GetPasteHandler retrieves a paste by its ID
+// GetPasteHandler retrieves a paste by its slug
 //
 // @Summary      Retrieve a paste
-// @Description  Retrieves a paste from the database by its ID.
+// @Description  Retrieves a paste from the database by its slug.
+// @Description  Protected pastes require the password unless the user is the owner or a collaborator.
+// @Description  Pastes with limited views are deleted after the last allowed read.
+// @Description  Conditional requests are answered with 304 without counting a view.
 // @Tags         pastes
 // @Produce      json
-// @Param        id   path   int   true       "Paste ID"
+// @Param        slug   path   string   true       "Paste slug"
+// @Param        X-Paste-Password   header   string   false       "Password of a protected paste"
+// @Param        If-None-Match   header   string   false   "ETag of the cached paste"
+// @Param        If-Modified-Since   header   string   false   "Last-Modified of the cached paste"
 // @Success      200  {object}  PasteResp  "Successfully retrieved paste"
+// @Header       200  {string}  ETag  "Entity tag of the paste version"
+// @Header       200  {string}  Last-Modified  "Time of the last update"
+// @Header       200  {string}  Cache-Control  "Caching rules based on the paste expiry"
+// @Success      304  "Paste has not changed"
+// @Failure      401  {object}  ErrorResponse "Paste password required"
+// @Failure      403  {object}  ErrorResponse "Invalid paste password"
 // @Failure      404  {object}  ErrorResponse "Paste not found"
 // @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
 // @Failure      500  {object}  ErrorResponse "Internal server error"
-// @Router       /api/v1/pastes/{id} [get]
+// @Router       /api/v1/pastes/{slug} [get]
 func (h *Handler) GetPasteHandler(w http.ResponseWriter, r *http.Request) {
-	id, err := helpers.ReadIDParam(r)
+	paste, headers, ok := h.readPaste(w, r)
+	if !ok {
+		return
+	}
+
+	err := helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"paste": paste}, headers)
 	if err != nil {
-		h.NotFoundResponse(w, r)
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// GetRawPasteHandler retrieves the text of a paste by its slug
+//
+// @Summary      Retrieve a raw paste
+// @Description  Retrieves the text of a paste as text/plain. Supports range requests.
+// @Description  Expiry, visibility, password and view limits are the same as for the JSON representation,
+// @Description  so every request to a paste with limited views, including range requests, is counted.
+// @Tags         pastes
+// @Produce      plain
+// @Param        slug   path   string   true       "Paste slug"
+// @Param        download   query   bool   false       "Send the paste as an attachment"
+// @Param        X-Paste-Password   header   string   false       "Password of a protected paste"
+// @Param        If-None-Match   header   string   false   "ETag of the cached paste"
+// @Param        If-Modified-Since   header   string   false   "Last-Modified of the cached paste"
+// @Param        Range   header   string   false   "Byte ranges of the text"
+// @Success      200  {string}  string  "Paste text"
+// @Success      206  {string}  string  "Requested ranges of the paste text"
+// @Header       200  {string}  Content-Disposition  "Suggested file name"
+// @Success      304  "Paste has not changed"
+// @Failure      401  {object}  ErrorResponse "Paste password required"
+// @Failure      403  {object}  ErrorResponse "Invalid paste password"
+// @Failure      404  {object}  ErrorResponse "Paste not found"
+// @Failure      416  "Requested range not satisfiable"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/raw [get]
+func (h *Handler) GetRawPasteHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+	download := helpers.ReadBool(r.URL.Query(), "download", false, v)
+	if !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	paste, headers, ok := h.readPaste(w, r)
+	if !ok {
 		return
 	}
 
-	paste, err := h.models.Pastes.Read(uint16(id))
+	for key, value := range headers {
+		w.Header()[key] = value
+	}
+
+	disposition := "inline"
+	if download {
+		disposition = "attachment"
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": paste.Slug + langdetect.Extension(paste.Language)}))
+
+	http.ServeContent(w, r, "", paste.UpdatedAt, strings.NewReader(paste.Text))
+}
+
+// readPaste fetches the paste from the slug parameter for a reader and applies the rules shared
+// by all its representations: expiry, visibility, password, conditional requests and view limits.
+// It returns the paste with its caching headers, or false if the response has already been written.
+func (h *Handler) readPaste(w http.ResponseWriter, r *http.Request) (*models.Paste, http.Header, bool) {
+	slug, err := helpers.ReadSlugParam(r)
+	if err != nil {
+		h.NotFoundResponse(w, r)
+		return nil, nil, false
+	}
+
+	paste, err := h.models.Pastes.Get(slug)
 	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
@@ -102,37 +274,89 @@ func (h *Handler) GetPasteHandler(w http.ResponseWriter, r *http.Request) {
 		default:
 			h.ServerErrorResponse(w, r, err)
 		}
-		return
+		return nil, nil, false
 	}
 
-	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"paste": paste}, nil)
-	if err != nil {
-		h.ServerErrorResponse(w, r, err)
+	if !h.authorizePasteRead(w, r, paste) {
+		return nil, nil, false
+	}
+
+	// the response depends on the password as well as on the user the middleware varies by
+	w.Header().Add("Vary", "X-Paste-Password")
+
+	headers := pasteCacheHeaders(paste)
+	if helpers.NotModified(r, paste.ETag(), paste.UpdatedAt) {
+		helpers.WriteNotModified(w, headers)
+		return nil, nil, false
+	}
+
+	// only pastes with limited views need the counting read, the others are already fetched
+	if paste.MaxViews != nil {
+		paste, err = h.models.Pastes.Read(slug)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrRecordNotFound):
+				h.NotFoundResponse(w, r)
+			default:
+				h.ServerErrorResponse(w, r, err)
+			}
+			return nil, nil, false
+		}
+		headers = pasteCacheHeaders(paste)
+	}
+
+	return paste, headers, true
+}
+
+// pasteMaxAge caps how long clients may reuse a paste without revalidating it,
+// as it can be edited long before it expires.
+const pasteMaxAge = time.Minute
+
+// pasteCacheHeaders returns the validators and caching rules for the paste response.
+// Pastes with limited views are never stored, since every read is counted.
+func pasteCacheHeaders(paste *models.Paste) http.Header {
+	headers := make(http.Header)
+	headers.Set("ETag", paste.ETag())
+	headers.Set("Last-Modified", paste.UpdatedAt.UTC().Format(http.TimeFormat))
+
+	if paste.MaxViews != nil {
+		headers.Set("Cache-Control", "no-store")
+		return headers
+	}
+
+	scope := "public"
+	if paste.Visibility == models.VisibilityPrivate || paste.Protected {
+		scope = "private"
 	}
+
+	maxAge := max(min(time.Until(paste.ExpiresAt), pasteMaxAge), 0)
+	headers.Set("Cache-Control", fmt.Sprintf("%s, max-age=%d, must-revalidate", scope, int(maxAge.Seconds())))
+
+	return headers
 }
 
-// DeletePasteHandler deletes a paste by its ID
+// DeletePasteHandler deletes a paste by its slug
 //
 // @Summary      Deletes a paste
-// @Description  Deletes a paste from the database by its ID.
+// @Description  Deletes a paste from the database by its slug.
 // @Tags         pastes
 // @Produce      json
 // @Security Bearer
-// @Param        id   path     int   true   "Paste ID"
+// @Param        slug   path     string   true   "Paste slug"
 // @Success      204  "Successfully deleted paste"
-// @Failure      403  {object}  ErrorResponse "User is not allowed to edit this paste"
+// @Failure      403  {object}  ErrorResponse "User is not the owner of this paste"
 // @Failure      404  {object} ErrorResponse "Paste not found"
 // @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
 // @Failure      500  {object} ErrorResponse "Internal server error"
-// @Router       /api/v1/pastes/{id} [delete]
+// @Router       /api/v1/pastes/{slug} [delete]
 func (h *Handler) DeletePasteHandler(w http.ResponseWriter, r *http.Request) {
-	id, err := helpers.ReadIDParam(r)
+	slug, err := helpers.ReadSlugParam(r)
 	if err != nil {
 		h.NotFoundResponse(w, r)
 		return
 	}
 
-	err = h.models.Pastes.Delete(uint16(id))
+	err = h.models.Pastes.Delete(slug)
 	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
@@ -150,22 +374,49 @@ func (h *Handler) DeletePasteHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 type CreatePasteInput struct {
-	Title    string `json:"title"`
-	Category uint8  `json:"category,omitempty"`
-	Text     string `json:"text"`
-	Minutes  int32  `json:"minutes"`
+	Title           string   `json:"title"`
+	Category        uint8    `json:"category,omitempty"`
+	Text            string   `json:"text"`
+	Language        string   `json:"language,omitempty"`
+	Format          string   `json:"format,omitempty"`
+	NaturalLanguage string   `json:"natural_language,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Minutes         int32    `json:"minutes"`
+	Visibility      string   `json:"visibility,omitempty"`
+	Password        string   `json:"password,omitempty"`
+	BurnAfterRead   bool     `json:"burn_after_read,omitempty"`
+	MaxViews        *int32   `json:"max_views,omitempty"`
 }
 
 // CreatePasteHandler creates a new paste by input data
 //
 // @Summary      Create a new paste
 // @Description  Creates a new paste in the database by input data.
+// @Description  Besides JSON, the text can be sent as a text/plain body or as the file field of a multipart/form-data upload.
+// @Description  Then the other fields are read from form fields, query parameters or X-Paste-* headers, e.g. X-Paste-Max-Views.
+// @Description  The password is read from the form field or the X-Paste-Password header only.
+// @Description  The language is detected from the title, the shebang line and keywords unless it is set.
+// @Description  The format defaults to markdown for Markdown pastes and to plain for the others.
+// @Description  The natural language the paste is searched in is detected from the text unless it is set.
 // @Tags         pastes
-// @Accept       json
+// @Accept       json,plain,mpfd
 // @Produce      json
-// @Param        body  body     CreatePasteInput  true  "Paste creation input"
+// @Param        body  body     CreatePasteInput  false  "Paste creation input"
+// @Param        file  formData  file  false  "Paste text file, its name is the default title"
+// @Param        title  query  string  false  "Title of a text/plain or multipart paste"
+// @Param        category  query  int  false  "Category of a text/plain or multipart paste"
+// @Param        minutes  query  int  false  "Lifetime of a text/plain or multipart paste"
+// @Param        language  query  string  false  "Language of a text/plain or multipart paste, detected if omitted"
+// @Param        format  query  string  false  "Format of a text/plain or multipart paste, plain or markdown"
+// @Param        tags  query  string  false  "Comma-separated tags of a text/plain or multipart paste"
+// @Param        natural_language  query  string  false  "Natural language of a text/plain or multipart paste, detected if omitted"
+// @Param        visibility  query  string  false  "Visibility of a text/plain or multipart paste"
+// @Param        burn_after_read  query  bool  false  "Burn a text/plain or multipart paste after reading"
+// @Param        max_views  query  int  false  "View limit of a text/plain or multipart paste"
+// @Param        X-Paste-Password  header  string  false  "Password of a text/plain or multipart paste"
 // @Success      201  {object}  PasteResp  "Successfully created paste"
 // @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      415  {object}  ErrorResponse "Unsupported content type"
 // @Failure      422  {object}  ErrorResponse "Unprocessable data"
 // @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
 // @Failure      500  {object}  ErrorResponse "Internal server error"
@@ -173,43 +424,100 @@ type CreatePasteInput struct {
 func (h *Handler) CreatePasteHandler(w http.ResponseWriter, r *http.Request) {
 	var in CreatePasteInput
 
-	err := helpers.ReadJSON(w, r, &in)
+	v := validator.New()
+
+	err := h.readCreatePasteInput(w, r, &in, v)
 	if err != nil {
-		h.BadRequestResponse(w, r, err)
+		switch {
+		case errors.Is(err, errUnsupportedMediaType):
+			h.UnsupportedMediaTypeResponse(w, r)
+		default:
+			h.BadRequestResponse(w, r, err)
+		}
+		return
+	}
+	if !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
 		return
 	}
 
+	if in.Visibility == "" {
+		in.Visibility = models.VisibilityPublic
+	}
+
+	// a burn after read paste is the one limited to a single view
+	if in.BurnAfterRead && in.MaxViews == nil {
+		in.MaxViews = new(int32)
+		*in.MaxViews = 1
+	}
+
+	// the title often is a file name, which is the best hint
+	if in.Language == "" {
+		in.Language = langdetect.Detect(in.Title, in.Text)
+	}
+	if in.Format == "" {
+		in.Format = models.FormatPlain
+		if in.Language == "markdown" {
+			in.Format = models.FormatMarkdown
+		}
+	}
+	if in.NaturalLanguage == "" {
+		in.NaturalLanguage = textlang.Detect(in.Title + "\n" + in.Text)
+	}
+
 	paste := &models.Paste{
-		Title:    in.Title,
-		Category: in.Category,
-		Text:     in.Text,
-		Minutes:  in.Minutes,
-		Version:  1,
+		Title:           in.Title,
+		Category:        in.Category,
+		Text:            in.Text,
+		Language:        in.Language,
+		Format:          in.Format,
+		NaturalLanguage: in.NaturalLanguage,
+		Tags:            models.NormalizeTags(in.Tags),
+		Minutes:         in.Minutes,
+		Visibility:      in.Visibility,
+		BurnAfterRead:   in.BurnAfterRead,
+		MaxViews:        in.MaxViews,
+		Version:         1,
+	}
+
+	if user := auth.ContextGetUser(r); !user.IsAnonymous() {
+		paste.Owner = &user.ID
 	}
 
-	v := validator.New()
 	v.Check(paste.Minutes > 0, "minutes", "must be greater than zero")
-	if models.ValidatePaste(v, paste); !v.Valid() {
-		h.FailedValidationResponse(w, r, v.Errors)
-		return
+	if in.Password != "" {
+		models.ValidatePasswordPlaintext(v, in.Password)
 	}
 
-	err = h.models.Pastes.Create(paste)
+	categories, err := h.models.Categories.GetAll()
 	if err != nil {
 		h.ServerErrorResponse(w, r, err)
 		return
 	}
 
-	if user := auth.ContextGetUser(r); !user.IsAnonymous() {
-		err = h.models.Permissions.SetWritePermission(user.ID, paste.Id)
+	if models.ValidatePaste(v, paste, categories); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	paste.CategoryName, _ = categories.GetCategory(paste.Category)
+
+	if in.Password != "" {
+		err = paste.Password.Set(in.Password)
 		if err != nil {
 			h.ServerErrorResponse(w, r, err)
 			return
 		}
 	}
 
+	err = h.models.Pastes.Create(paste)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
 	headers := make(http.Header)
-	headers.Set("Location", fmt.Sprintf("api/v1/pastes/%d", paste.Id))
+	headers.Set("Location", fmt.Sprintf("api/v1/pastes/%s", paste.Slug))
 
 	err = helpers.WriteJSON(w, http.StatusCreated, helpers.Envelope{"paste": paste}, headers)
 	if err != nil {
@@ -217,39 +525,170 @@ func (h *Handler) CreatePasteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// readCreatePasteInput decodes the paste from a JSON, text/plain or multipart/form-data body.
+// Fields of the latter two which can not be parsed are reported to v.
+func (h *Handler) readCreatePasteInput(w http.ResponseWriter, r *http.Request, in *CreatePasteInput, v *validator.Validator) error {
+	limits := h.service.Config.Pastes
+
+	mediaType := "application/json"
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		var err error
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return errUnsupportedMediaType
+		}
+	}
+
+	switch mediaType {
+	case "application/json":
+		return helpers.ReadJSONLimited(w, r, in, limits.MaxJSONSize)
+
+	case "text/plain":
+		text, err := helpers.ReadText(w, r, limits.MaxTextSize)
+		if err != nil {
+			return err
+		}
+		in.Text = text
+
+	case "multipart/form-data":
+		text, filename, err := helpers.ReadFormFile(w, r, "file", limits.MaxMultipartSize)
+		if err != nil {
+			return err
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		in.Text = text
+		in.Title = filepath.Base(filename)
+
+	default:
+		return errUnsupportedMediaType
+	}
+
+	readCreatePasteFields(r, in, v)
+
+	return nil
+}
+
+// readCreatePasteFields fills the fields of a paste sent without JSON. Each field is looked up
+// in the form, the query string and then the X-Paste-* header, e.g. X-Paste-Max-Views for max_views.
+func readCreatePasteFields(r *http.Request, in *CreatePasteInput, v *validator.Validator) {
+	field := func(key string) (string, bool) {
+		if value := r.FormValue(key); value != "" {
+			return value, true
+		}
+		header := "X-Paste-" + strings.ReplaceAll(key, "_", "-")
+		if value := r.Header.Get(header); value != "" {
+			return value, true
+		}
+		return "", false
+	}
+
+	readInt := func(key string, bitSize int) (int64, bool) {
+		value, ok := field(key)
+		if !ok {
+			return 0, false
+		}
+		number, err := strconv.ParseInt(value, 10, bitSize)
+		if err != nil {
+			v.AddError(key, "must be an integer value")
+			return 0, false
+		}
+		return number, true
+	}
+
+	if title, ok := field("title"); ok {
+		in.Title = title
+	}
+	if category, ok := readInt("category", 16); ok {
+		if category < 0 || category > math.MaxUint8 {
+			v.AddError("category", "no such category")
+		}
+		in.Category = uint8(category)
+	}
+	if minutes, ok := readInt("minutes", 32); ok {
+		in.Minutes = int32(minutes)
+	}
+	if language, ok := field("language"); ok {
+		in.Language = language
+	}
+	if format, ok := field("format"); ok {
+		in.Format = format
+	}
+	if naturalLanguage, ok := field("natural_language"); ok {
+		in.NaturalLanguage = naturalLanguage
+	}
+	if tags, ok := field("tags"); ok {
+		in.Tags = strings.Split(tags, ",")
+	}
+	if visibility, ok := field("visibility"); ok {
+		in.Visibility = visibility
+	}
+	// the password never comes from the query, which ends up in access logs and browser history
+	if password := r.PostFormValue("password"); password != "" {
+		in.Password = password
+	} else if password = r.Header.Get("X-Paste-Password"); password != "" {
+		in.Password = password
+	}
+	if burn, ok := field("burn_after_read"); ok {
+		value, err := strconv.ParseBool(burn)
+		if err != nil {
+			v.AddError("burn_after_read", "must be a boolean value")
+		}
+		in.BurnAfterRead = value
+	}
+	if maxViews, ok := readInt("max_views", 32); ok {
+		in.MaxViews = new(int32)
+		*in.MaxViews = int32(maxViews)
+	}
+}
+
 type UpdatePasteInput struct {
-	Title    *string `json:"title"`
-	Category *uint8  `json:"category,omitempty"`
-	Text     *string `json:"text"`
-	Minutes  *int32  `json:"minutes"`
+	Title           *string   `json:"title"`
+	Category        *uint8    `json:"category,omitempty"`
+	Text            *string   `json:"text"`
+	Language        *string   `json:"language,omitempty"`
+	Format          *string   `json:"format,omitempty"`
+	NaturalLanguage *string   `json:"natural_language,omitempty"`
+	Tags            *[]string `json:"tags,omitempty"`
+	Minutes         *int32    `json:"minutes"`
+	Visibility      *string   `json:"visibility,omitempty"`
+	Password        *string   `json:"password,omitempty"`
+	Version         *uint32   `json:"version,omitempty"`
 }
 
-// UpdatePasteHandler updates a new paste by ID and input data
+// UpdatePasteHandler updates a new paste by slug and input data
 //
 // @Summary      Update the paste
-// @Description  Updates the paste in the database by ID and input data.
+// @Description  Updates the paste in the database by slug and input data.
+// @Description  The version the client has edited can be passed as an ETag in the If-Match header or in the version field.
 // @Tags         pastes
 // @Accept       json
 // @Produce      json
-// @Param        id   path     int   true   "Paste ID"
+// @Param        slug   path     string   true   "Paste slug"
+// @Param        If-Match   header   string   false   "ETag of the edited version"
 // @Param        body  body     UpdatePasteInput  false  "Paste update input"
 // @Security Bearer
 // @Success      200  {object}  PasteResp  "Successfully updated paste"
+// @Header       200  {string}  ETag  "Entity tag of the new paste version"
 // @Failure      400  {object}  ErrorResponse "Bad request"
-// @Failure      403  {object}  ErrorResponse "User is not allowed to edit this paste"
+// @Failure      403  {object}  ErrorResponse "User is not allowed to edit this paste or to change its visibility"
 // @Failure      404  {object}  ErrorResponse "Not found"
+// @Failure      409  {object}  ErrorResponse "Edit conflict, the version field does not match"
+// @Failure      412  {object}  ErrorResponse "The If-Match header does not match"
 // @Failure      422  {object}  ErrorResponse "Unprocessable data"
 // @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
 // @Failure      500  {object}  ErrorResponse "Internal server error"
-// @Router       /api/v1/pastes/{id} [patch]
+// @Router       /api/v1/pastes/{slug} [patch]
 func (h *Handler) UpdatePasteHandler(w http.ResponseWriter, r *http.Request) {
-	id, err := helpers.ReadIDParam(r)
+	slug, err := helpers.ReadSlugParam(r)
 	if err != nil {
 		h.NotFoundResponse(w, r)
 		return
 	}
 
-	paste, err := h.models.Pastes.Read(uint16(id))
+	paste, err := h.models.Pastes.Get(slug)
 	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
@@ -260,6 +699,11 @@ func (h *Handler) UpdatePasteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if match := r.Header.Get("If-Match"); match != "" && !helpers.MatchETag(match, paste.ETag(), false) {
+		h.PreconditionFailedResponse(w, r)
+		return
+	}
+
 	var in UpdatePasteInput
 
 	err = helpers.ReadJSON(w, r, &in)
@@ -268,6 +712,11 @@ func (h *Handler) UpdatePasteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if in.Version != nil && *in.Version != paste.Version {
+		h.EditConflictResponse(w, r)
+		return
+	}
+
 	if in.Title != nil {
 		paste.Title = strings.TrimSpace(*in.Title)
 	}
@@ -277,21 +726,69 @@ func (h *Handler) UpdatePasteHandler(w http.ResponseWriter, r *http.Request) {
 	if in.Text != nil {
 		paste.Text = strings.TrimSpace(*in.Text)
 	}
+	if in.Language != nil {
+		paste.Language = *in.Language
+	}
+	if in.Format != nil {
+		paste.Format = *in.Format
+	}
+	if in.NaturalLanguage != nil {
+		paste.NaturalLanguage = *in.NaturalLanguage
+	}
+	if in.Tags != nil {
+		paste.Tags = models.NormalizeTags(*in.Tags)
+	}
 	if in.Minutes != nil {
 		paste.Minutes = *in.Minutes
 		paste.ExpiresAt = paste.ExpiresAt.Add(time.Duration(paste.Minutes) * time.Minute)
 	}
 
+	// changing who can read the paste is up to the owner only
+	if in.Visibility != nil || in.Password != nil {
+		if user := auth.ContextGetUser(r); paste.Owner == nil || *paste.Owner != user.ID {
+			h.ForbiddenResponse(w, r)
+			return
+		}
+	}
+	if in.Visibility != nil {
+		paste.Visibility = *in.Visibility
+	}
+
 	v := validator.New()
 
+	// an empty password removes the protection
+	if in.Password != nil && *in.Password != "" {
+		if models.ValidatePasswordPlaintext(v, *in.Password); !v.Valid() {
+			h.FailedValidationResponse(w, r, v.Errors)
+			return
+		}
+	}
+
 	expiration := paste.ExpiresAt.Add(time.Duration(paste.Minutes) * time.Minute)
 	v.Check(expiration.After(paste.CreatedAt), "minutes", "paste can't be expired before creation")
 
-	if models.ValidatePaste(v, paste); !v.Valid() {
+	categories, err := h.models.Categories.GetAll()
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	if models.ValidatePaste(v, paste, categories); !v.Valid() {
 		h.FailedValidationResponse(w, r, v.Errors)
 		return
 	}
 
+	paste.CategoryName, _ = categories.GetCategory(paste.Category)
+
+	if in.Password != nil {
+		if *in.Password == "" {
+			paste.Password.Clear()
+		} else if err = paste.Password.Set(*in.Password); err != nil {
+			h.ServerErrorResponse(w, r, err)
+			return
+		}
+	}
+
 	err = h.models.Pastes.Update(paste)
 	if err != nil {
 		switch {
@@ -303,8 +800,167 @@ func (h *Handler) UpdatePasteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"paste": paste}, nil)
+	headers := make(http.Header)
+	headers.Set("ETag", paste.ETag())
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"paste": paste}, headers)
 	if err != nil {
 		h.ServerErrorResponse(w, r, err)
 	}
 }
+
+type TransferPasteInput struct {
+	Login string `json:"login"`
+}
+
+// TransferPasteHandler hands the paste over to another user
+//
+// @Summary      Transfer the paste
+// @Description  Makes another user the owner of the paste. Only the current owner can do it.
+// @Description  The version of the paste stays the same, the ETag changes.
+// @Tags         pastes
+// @Accept       json
+// @Produce      json
+// @Param        slug   path     string   true   "Paste slug"
+// @Param        If-Match   header   string   false   "ETag of the transferred version"
+// @Param        body  body     TransferPasteInput  true  "New owner"
+// @Security Bearer
+// @Success      200  {object}  PasteResp  "Successfully transferred paste"
+// @Header       200  {string}  ETag  "Entity tag of the transferred paste"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      403  {object}  ErrorResponse "User is not the owner of this paste"
+// @Failure      404  {object}  ErrorResponse "Not found"
+// @Failure      409  {object}  ErrorResponse "Edit conflict"
+// @Failure      412  {object}  ErrorResponse "The If-Match header does not match"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/owner [put]
+func (h *Handler) TransferPasteHandler(w http.ResponseWriter, r *http.Request) {
+	slug, err := helpers.ReadSlugParam(r)
+	if err != nil {
+		h.NotFoundResponse(w, r)
+		return
+	}
+
+	var in TransferPasteInput
+
+	err = helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if models.ValidateLogin(v, in.Login); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	paste, err := h.models.Pastes.Get(slug)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if match := r.Header.Get("If-Match"); match != "" && !helpers.MatchETag(match, paste.ETag(), false) {
+		h.PreconditionFailedResponse(w, r)
+		return
+	}
+
+	newOwner, err := h.models.Users.GetByLogin(in.Login)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			v.AddError("login", "no user with this login")
+			h.FailedValidationResponse(w, r, v.Errors)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	v.Check(newOwner.Activated, "login", "user account must be activated")
+	v.Check(newOwner.ID != auth.ContextGetUser(r).ID, "login", "the user already owns this paste")
+	if !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = h.models.Pastes.Transfer(paste, newOwner.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEditConflict):
+			h.EditConflictResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", paste.ETag())
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"paste": paste}, headers)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// authorizePasteRead checks that the request may see the paste and sends the error response
+// if it may not. Private pastes are hidden from everyone but the owner and collaborators,
+// protected pastes require the password from the X-Paste-Password header from anyone else.
+func (h *Handler) authorizePasteRead(w http.ResponseWriter, r *http.Request, paste *models.Paste) bool {
+	if paste.Visibility != models.VisibilityPrivate && !paste.Protected {
+		return true
+	}
+
+	member, err := h.isPasteMember(auth.ContextGetUser(r), paste)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return false
+	}
+
+	switch {
+	case member:
+		return true
+	case paste.Visibility == models.VisibilityPrivate:
+		h.NotFoundResponse(w, r)
+		return false
+	}
+
+	password := r.Header.Get("X-Paste-Password")
+	if password == "" {
+		h.PastePasswordRequiredResponse(w, r)
+		return false
+	}
+
+	match, err := paste.Password.Matches(password)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return false
+	}
+
+	if !match {
+		h.InvalidPastePasswordResponse(w, r)
+		return false
+	}
+
+	return true
+}
+
+// isPasteMember reports whether the user is the owner or a collaborator of the paste.
+func (h *Handler) isPasteMember(user *models.User, paste *models.Paste) (bool, error) {
+	if user.IsAnonymous() {
+		return false, nil
+	}
+	if paste.Owner != nil && *paste.Owner == user.ID {
+		return true, nil
+	}
+	return h.models.Permissions.IsCollaborator(user.ID, paste.Id)
+}
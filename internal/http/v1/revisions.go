@@ -0,0 +1,253 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"pasteAPI/internal/repository"
+	"pasteAPI/internal/repository/models"
+	"pasteAPI/pkg/diff"
+	"pasteAPI/pkg/helpers"
+	"pasteAPI/pkg/validator"
+)
+
+type ListRevisionsOutput struct {
+	R []*models.Revision `json:"revisions"`
+}
+
+type RevisionResp struct {
+	R *models.Revision `json:"revision"`
+}
+
+type DiffOutput struct {
+	From uint32 `json:"from"`
+	To   uint32 `json:"to"`
+	Diff string `json:"diff"`
+}
+
+// ListRevisionsHandler retrieves the history of the paste
+//
+// @Summary      List revisions
+// @Description  Retrieves all versions of the paste, newest first, without their text.
+// @Tags         revisions
+// @Produce      json
+// @Param        slug   path     string   true   "Paste slug"
+// @Security Bearer
+// @Success      200  {object}  ListRevisionsOutput  "Successfully retrieved revisions"
+// @Failure      403  {object}  ErrorResponse "User is not allowed to edit this paste"
+// @Failure      404  {object}  ErrorResponse "Paste not found"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/revisions [get]
+func (h *Handler) ListRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	paste, ok := h.getPasteFromParam(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.models.Revisions.GetAll(paste.Id)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"revisions": revisions}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// GetRevisionHandler retrieves the paste content at the given version
+//
+// @Summary      Retrieve a revision
+// @Description  Retrieves the title and text of the paste at the given version.
+// @Tags         revisions
+// @Produce      json
+// @Param        slug   path     string   true   "Paste slug"
+// @Param        version   path     int   true   "Paste version"
+// @Security Bearer
+// @Success      200  {object}  RevisionResp  "Successfully retrieved revision"
+// @Failure      403  {object}  ErrorResponse "User is not allowed to edit this paste"
+// @Failure      404  {object}  ErrorResponse "Paste or revision not found"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/revisions/{version} [get]
+func (h *Handler) GetRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	version, err := helpers.ReadVersionParam(r)
+	if err != nil {
+		h.NotFoundResponse(w, r)
+		return
+	}
+
+	paste, ok := h.getPasteFromParam(w, r)
+	if !ok {
+		return
+	}
+
+	revision, ok := h.getRevision(w, r, paste.Id, version)
+	if !ok {
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"revision": revision}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// DiffRevisionsHandler compares two versions of the paste
+//
+// @Summary      Compare revisions
+// @Description  Returns the unified diff of the paste text between two versions.
+// @Description  By default the current version is compared with the previous one.
+// @Tags         revisions
+// @Produce      json
+// @Param        slug   path     string   true   "Paste slug"
+// @Param        from   query    int     false  "Old version"
+// @Param        to     query    int     false  "New version"
+// @Security Bearer
+// @Success      200  {object}  DiffOutput  "Successfully compared revisions"
+// @Failure      403  {object}  ErrorResponse "User is not allowed to edit this paste"
+// @Failure      404  {object}  ErrorResponse "Paste or revision not found"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/diff [get]
+func (h *Handler) DiffRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	paste, ok := h.getPasteFromParam(w, r)
+	if !ok {
+		return
+	}
+
+	qs := r.URL.Query()
+
+	v := validator.New()
+	to := uint32(helpers.ReadInt(qs, "to", int(paste.Version), v))
+	from := uint32(helpers.ReadInt(qs, "from", int(max(to, 2)-1), v))
+
+	v.Check(from > 0, "from", "must be greater than zero")
+	v.Check(to > 0, "to", "must be greater than zero")
+	if !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	oldRevision, ok := h.getRevision(w, r, paste.Id, from)
+	if !ok {
+		return
+	}
+
+	newRevision, ok := h.getRevision(w, r, paste.Id, to)
+	if !ok {
+		return
+	}
+
+	unified := diff.Unified(fmt.Sprintf("v%d", from), fmt.Sprintf("v%d", to), oldRevision.Text, newRevision.Text, 3)
+
+	err := helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"from": from, "to": to, "diff": unified}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// RestoreRevisionHandler brings back the content of an old version
+//
+// @Summary      Restore a revision
+// @Description  Saves the title and text of the given version as a new version of the paste.
+// @Tags         revisions
+// @Produce      json
+// @Param        slug   path     string   true   "Paste slug"
+// @Param        version   path     int   true   "Paste version"
+// @Param        If-Match   header   string   false   "ETag of the current version"
+// @Security Bearer
+// @Success      200  {object}  PasteResp  "Successfully restored revision"
+// @Header       200  {string}  ETag  "Entity tag of the new paste version"
+// @Failure      403  {object}  ErrorResponse "User is not allowed to edit this paste"
+// @Failure      404  {object}  ErrorResponse "Paste or revision not found"
+// @Failure      409  {object}  ErrorResponse "Edit conflict"
+// @Failure      412  {object}  ErrorResponse "The If-Match header does not match"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/pastes/{slug}/revisions/{version}/restore [post]
+func (h *Handler) RestoreRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	version, err := helpers.ReadVersionParam(r)
+	if err != nil {
+		h.NotFoundResponse(w, r)
+		return
+	}
+
+	paste, ok := h.getPasteFromParam(w, r)
+	if !ok {
+		return
+	}
+
+	if match := r.Header.Get("If-Match"); match != "" && !helpers.MatchETag(match, paste.ETag(), false) {
+		h.PreconditionFailedResponse(w, r)
+		return
+	}
+
+	revision, ok := h.getRevision(w, r, paste.Id, version)
+	if !ok {
+		return
+	}
+
+	paste.Title = revision.Title
+	paste.Text = revision.Text
+
+	err = h.models.Pastes.Update(paste)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEditConflict):
+			h.EditConflictResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", paste.ETag())
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"paste": paste}, headers)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// getPasteFromParam fetches the paste addressed by the slug URL parameter and sends
+// the error response if it fails.
+func (h *Handler) getPasteFromParam(w http.ResponseWriter, r *http.Request) (*models.Paste, bool) {
+	slug, err := helpers.ReadSlugParam(r)
+	if err != nil {
+		h.NotFoundResponse(w, r)
+		return nil, false
+	}
+
+	paste, err := h.models.Pastes.Get(slug)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return nil, false
+	}
+
+	return paste, true
+}
+
+func (h *Handler) getRevision(w http.ResponseWriter, r *http.Request, pasteId int64, version uint32) (*models.Revision, bool) {
+	revision, err := h.models.Revisions.Get(pasteId, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return nil, false
+	}
+
+	return revision, true
+}
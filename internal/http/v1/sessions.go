@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"pasteAPI/internal/auth"
+	"pasteAPI/internal/repository"
+	"pasteAPI/internal/repository/models"
+	"pasteAPI/pkg/helpers"
+)
+
+type SessionsResp struct {
+	Sessions []*models.Session `json:"sessions"`
+}
+
+// ListSessionsHandler retrieves the active sessions of the current user
+//
+// @Summary      List my sessions
+// @Description  Retrieves the unexpired authentication tokens of the current user, the recently used first.
+// @Description  The session the request is authenticated by is marked as current.
+// @Tags         users
+// @Produce      json
+// @Security Bearer
+// @Success      200  {object}  SessionsResp  "Successfully retrieved sessions"
+// @Failure      401  {object}  ErrorResponse "Authentication required"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/me/sessions [get]
+func (h *Handler) ListSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	user := auth.ContextGetUser(r)
+
+	sessions, err := h.models.Tokens.GetSessions(user.ID, auth.ContextGetToken(r))
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Cache-Control", "private, no-store")
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"sessions": sessions}, headers)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// DeleteSessionHandler revokes a session of the current user
+//
+// @Summary      Revoke my session
+// @Description  Revokes the authentication token with the ID, e.g., one left on a lost device.
+// @Tags         users
+// @Param        id   path     int  true  "Session ID"
+// @Security Bearer
+// @Success      204  "Successfully revoked"
+// @Failure      401  {object}  ErrorResponse "Authentication required"
+// @Failure      404  {object}  ErrorResponse "Session not found"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/me/sessions/{id} [delete]
+func (h *Handler) DeleteSessionHandler(w http.ResponseWriter, r *http.Request) {
+	user := auth.ContextGetUser(r)
+
+	id, err := helpers.ReadIDParam(r)
+	if err != nil || id < 1 {
+		h.NotFoundResponse(w, r)
+		return
+	}
+
+	err = h.models.Tokens.DeleteSession(user.ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusNoContent, nil, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"net/http"
+	"pasteAPI/internal/repository/models"
+	"pasteAPI/pkg/helpers"
+	"pasteAPI/pkg/validator"
+)
+
+type ListTagsOutput struct {
+	Tags []*models.TagCount `json:"tags"`
+}
+
+// ListTagsHandler retrieves the most used tags
+//
+// @Summary      List tags
+// @Description  Retrieves the tags of public pastes with the number of pastes for each, the most used first.
+// @Tags         tags
+// @Produce      json
+// @Param        limit  query  int  false  "Maximum number of tags, 50 by default"
+// @Success      200  {object}  ListTagsOutput  "Successfully retrieved tags"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/tags [get]
+func (h *Handler) ListTagsHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+	limit := helpers.ReadInt(r.URL.Query(), "limit", 50, v)
+
+	v.Check(limit > 0, "limit", "must be greater than zero")
+	v.Check(limit <= 500, "limit", "must be a maximum of 500")
+	if !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	tags, err := h.models.Tags.GetAll(limit)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"tags": tags}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
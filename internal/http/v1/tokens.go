@@ -2,12 +2,16 @@ package v1
 
 import (
 	"errors"
+	"net"
 	"net/http"
+	"pasteAPI/internal/auth"
 	"pasteAPI/internal/repository"
 	"pasteAPI/internal/repository/models"
 	"pasteAPI/pkg/helpers"
 	"pasteAPI/pkg/validator"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type AuthInput struct {
@@ -74,7 +78,8 @@ func (h *Handler) CreateAuthenticationTokenHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	token, err := h.models.Tokens.New(user.ID, 24*time.Hour, repository.ScopeAuthentication)
+	userAgent, ip := clientInfo(r)
+	token, err := h.models.Tokens.NewSession(user.ID, 24*time.Hour, userAgent, ip)
 	if err != nil {
 		h.ServerErrorResponse(w, r, err)
 		return
@@ -85,3 +90,207 @@ func (h *Handler) CreateAuthenticationTokenHandler(w http.ResponseWriter, r *htt
 		h.ServerErrorResponse(w, r, err)
 	}
 }
+
+// DeleteAuthenticationTokenHandler revokes the token the request is authenticated by
+//
+// @Summary      Logout
+// @Description  Revokes the authentication token of the request. Other sessions of the user stay valid.
+// @Tags         tokens
+// @Security Bearer
+// @Success      204  "Successfully revoked"
+// @Failure      401  {object}  ErrorResponse "Authentication required"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/tokens/authentication [delete]
+func (h *Handler) DeleteAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
+	err := h.models.Tokens.DeleteForToken(repository.ScopeAuthentication, auth.ContextGetToken(r))
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusNoContent, nil, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+// clientInfo returns the user agent and the IP address of the client making the request.
+func clientInfo(r *http.Request) (string, string) {
+	userAgent := r.UserAgent()
+	if utf8.RuneCountInString(userAgent) > models.MaxUserAgentLength {
+		userAgent = string([]rune(userAgent)[:models.MaxUserAgentLength])
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return userAgent, ip
+}
+
+type PasswordResetInput struct {
+	Email string `json:"email"`
+}
+
+type MessageResp struct {
+	Message string `json:"message"`
+}
+
+// CreatePasswordResetTokenHandler sends a password reset token to the email of the user
+//
+// @Summary      Password reset request
+// @Description  Sends a password reset code to the email if an account uses it.
+// @Description  The response is the same whether the account exists or not, so it does not tell which emails are registered.
+// @Description  Emails to one address are rate limited.
+// @Tags         tokens
+// @Accept       json
+// @Produce      json
+// @Param        body  body     PasswordResetInput  true  "Password reset request input"
+// @Success      202  {object}  MessageResp  "Successfully accepted"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/tokens/password-reset [post]
+func (h *Handler) CreatePasswordResetTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var in PasswordResetInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if models.ValidateEmail(v, in.Email); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	if !h.resetEmails.Allow(strings.ToLower(in.Email)) {
+		h.RateLimitExceededResponse(w, r)
+		return
+	}
+
+	// the lookup runs after the response, so its timing does not tell whether the account exists
+	h.service.Background(func() {
+		user, err := h.models.Users.GetByEmail(in.Email)
+		if err != nil {
+			if !errors.Is(err, repository.ErrRecordNotFound) {
+				h.service.Logger.Error(err)
+			}
+			return
+		}
+
+		err = h.models.Tokens.DeleteAllForUser(repository.ScopePasswordReset, user.ID)
+		if err != nil {
+			h.service.Logger.Error(err)
+			return
+		}
+
+		token, err := h.models.Tokens.New(user.ID, 45*time.Minute, repository.ScopePasswordReset)
+		if err != nil {
+			h.service.Logger.Error(err)
+			return
+		}
+
+		tmplData := map[string]interface{}{
+			"passwordResetCode": token.Plaintext,
+			"Login":             user.Login,
+		}
+		err = h.service.Mailer.SendEmail(user.Email, "password_reset.tmpl", tmplData)
+		if err != nil {
+			h.service.Logger.Error(err)
+		}
+	})
+
+	msg := "an email will be sent to you containing password reset instructions if the account exists"
+	err = helpers.WriteJSON(w, http.StatusAccepted, helpers.Envelope{"message": msg}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+type ActivationTokenInput struct {
+	Email string `json:"email"`
+}
+
+// CreateActivationTokenHandler sends a new activation token to the email of the user
+//
+// @Summary      Activation email resend
+// @Description  Revokes the activation codes sent before and sends a new one to the email if an account
+// @Description  which is not activated yet uses it. The response is the same whether the account exists or not.
+// @Description  Emails to one address are rate limited.
+// @Tags         tokens
+// @Accept       json
+// @Produce      json
+// @Param        body  body     ActivationTokenInput  true  "Activation email resend input"
+// @Success      202  {object}  MessageResp  "Successfully accepted"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/tokens/activation [post]
+func (h *Handler) CreateActivationTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var in ActivationTokenInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if models.ValidateEmail(v, in.Email); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	if !h.activationEmails.Allow(strings.ToLower(in.Email)) {
+		h.RateLimitExceededResponse(w, r)
+		return
+	}
+
+	h.service.Background(func() {
+		user, err := h.models.Users.GetByEmail(in.Email)
+		if err != nil {
+			if !errors.Is(err, repository.ErrRecordNotFound) {
+				h.service.Logger.Error(err)
+			}
+			return
+		}
+		if user.Activated {
+			return
+		}
+
+		err = h.models.Tokens.DeleteAllForUser(repository.ScopeActivation, user.ID)
+		if err != nil {
+			h.service.Logger.Error(err)
+			return
+		}
+
+		token, err := h.models.Tokens.New(user.ID, 8*time.Hour, repository.ScopeActivation)
+		if err != nil {
+			h.service.Logger.Error(err)
+			return
+		}
+
+		tmplData := map[string]interface{}{
+			"activationCode": token.Plaintext,
+			"ID":             user.ID,
+			"Login":          user.Login,
+		}
+		err = h.service.Mailer.SendEmail(user.Email, "welcome.tmpl", tmplData)
+		if err != nil {
+			h.service.Logger.Error(err)
+		}
+	})
+
+	msg := "an email will be sent to you containing activation instructions if the account is not activated yet"
+	err = helpers.WriteJSON(w, http.StatusAccepted, helpers.Envelope{"message": msg}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
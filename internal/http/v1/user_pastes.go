@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"pasteAPI/internal/auth"
+	"pasteAPI/internal/repository"
+	"pasteAPI/internal/repository/models"
+	"pasteAPI/pkg/helpers"
+	"pasteAPI/pkg/validator"
+)
+
+// ListMyPastesHandler retrieves the pastes of the current user
+//
+// @Summary      List my pastes
+// @Description  Retrieves the pastes the current user owns or collaborates on, including unlisted and private ones.
+// @Description  The role of the user is returned with each paste. Search, sort and paging are the same as for all pastes.
+// @Tags         users
+// @Produce      json
+// @Param        role      query    string  false  "Role of the user, owner or collaborator"
+// @Param        q         query    string  false  "Search query"
+// @Param        sort      query    string  false  "Sort order, e.g., -created_at"
+// @Param        page      query    int     false  "Page number for pagination"
+// @Param        pageSize  query    int     false  "Number of items per page"
+// @Param        after     query    string  false  "Cursor of the page to start after"
+// @Param        before    query    string  false  "Cursor of the page to end before"
+// @Param        count     query    bool    false  "Count the total of the matching pastes, false by default"
+// @Security Bearer
+// @Success      200  {object}  ListPastesOutput  "Successfully retrieved pastes"
+// @Success      304  "Listing has not changed"
+// @Failure      401  {object}  ErrorResponse "Authentication required"
+// @Failure      403  {object}  ErrorResponse "Inactive account"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/me/pastes [get]
+func (h *Handler) ListMyPastesHandler(w http.ResponseWriter, r *http.Request) {
+	user := auth.ContextGetUser(r)
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+	in := h.readSearchSettings(qs, v)
+	in.Search.UserID = user.ID
+	in.Search.Role = helpers.ReadString(qs, "role", "")
+	in.Search.AllVisibilities = true
+
+	if in.Search.Role != "" {
+		v.Check(validator.In(in.Search.Role, models.PasteRoleOwner, models.PasteRoleCollaborator), "role", "must be owner or collaborator")
+	}
+
+	if models.ValidateFilters(v, in.Filters); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	h.writePasteList(w, r, in, "private, no-cache")
+}
+
+// ListUserPastesHandler retrieves the public pastes of a user
+//
+// @Summary      List pastes of a user
+// @Description  Retrieves the public pastes the user owns or collaborates on.
+// @Description  Search, sort and paging are the same as for all pastes.
+// @Tags         users
+// @Produce      json
+// @Param        login     path     string  true   "User login"
+// @Param        role      query    string  false  "Role of the user, owner or collaborator"
+// @Param        q         query    string  false  "Search query"
+// @Param        sort      query    string  false  "Sort order, e.g., -created_at"
+// @Param        page      query    int     false  "Page number for pagination"
+// @Param        pageSize  query    int     false  "Number of items per page"
+// @Param        after     query    string  false  "Cursor of the page to start after"
+// @Param        before    query    string  false  "Cursor of the page to end before"
+// @Param        count     query    bool    false  "Count the total of the matching pastes, false by default"
+// @Success      200  {object}  ListPastesOutput  "Successfully retrieved pastes"
+// @Success      304  "Listing has not changed"
+// @Failure      404  {object}  ErrorResponse "User not found"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/{login}/pastes [get]
+func (h *Handler) ListUserPastesHandler(w http.ResponseWriter, r *http.Request) {
+	login := chi.URLParam(r, "login")
+	if !validator.Matches(login, validator.LoginRX) {
+		h.NotFoundResponse(w, r)
+		return
+	}
+
+	user, err := h.models.Users.GetByLogin(login)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+	in := h.readSearchSettings(qs, v)
+	in.Search.UserID = user.ID
+	in.Search.Role = helpers.ReadString(qs, "role", "")
+
+	if in.Search.Role != "" {
+		v.Check(validator.In(in.Search.Role, models.PasteRoleOwner, models.PasteRoleCollaborator), "role", "must be owner or collaborator")
+	}
+
+	if models.ValidateFilters(v, in.Filters); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	h.writePasteList(w, r, in, "public, no-cache")
+}
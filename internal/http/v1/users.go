@@ -3,10 +3,12 @@ package v1
 import (
 	"errors"
 	"net/http"
+	"pasteAPI/internal/auth"
 	"pasteAPI/internal/repository"
 	"pasteAPI/internal/repository/models"
 	"pasteAPI/pkg/helpers"
 	"pasteAPI/pkg/validator"
+	"strings"
 	"time"
 )
 
@@ -167,3 +169,451 @@ func (h *Handler) ActivateUserHandler(w http.ResponseWriter, r *http.Request) {
 		h.ServerErrorResponse(w, r, err)
 	}
 }
+
+// GetCurrentUserHandler retrieves the profile of the current user
+//
+// @Summary      My profile
+// @Description  Retrieves the profile of the current user.
+// @Tags         users
+// @Produce      json
+// @Security Bearer
+// @Success      200  {object}  UserResp  "Successfully retrieved profile"
+// @Failure      401  {object}  ErrorResponse "Authentication required"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/me [get]
+func (h *Handler) GetCurrentUserHandler(w http.ResponseWriter, r *http.Request) {
+	user := auth.ContextGetUser(r)
+
+	err := helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"user": user}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+type UpdateUserInput struct {
+	Login *string `json:"login"`
+	Email *string `json:"email"`
+}
+
+// UpdateCurrentUserHandler updates the profile of the current user
+//
+// @Summary      Update my profile
+// @Description  Changes the login and the email of the current user. The login is changed at once,
+// @Description  while the new email is kept as pending_email and replaces the email only after it is
+// @Description  confirmed with the code sent to the new address.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        body  body     UpdateUserInput  true  "Profile update input"
+// @Security Bearer
+// @Success      200  {object}  UserResp  "Successfully updated profile"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      401  {object}  ErrorResponse "Authentication required"
+// @Failure      403  {object}  ErrorResponse "Inactive account"
+// @Failure      409  {object}  ErrorResponse "Edit conflict"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/me [patch]
+func (h *Handler) UpdateCurrentUserHandler(w http.ResponseWriter, r *http.Request) {
+	user := auth.ContextGetUser(r)
+
+	var in UpdateUserInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	if in.Login != nil {
+		user.Login = *in.Login
+	}
+
+	// asking for the current email again cancels a pending change
+	emailChanged := false
+	if in.Email != nil {
+		if strings.EqualFold(*in.Email, user.Email) {
+			user.PendingEmail = nil
+		} else {
+			user.PendingEmail = in.Email
+			emailChanged = true
+		}
+	}
+
+	v := validator.New()
+
+	models.ValidateLogin(v, user.Login)
+	if user.PendingEmail != nil {
+		models.ValidateEmail(v, *user.PendingEmail)
+	}
+	if !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	if emailChanged {
+		_, err = h.models.Users.GetByEmail(*user.PendingEmail)
+		switch {
+		case err == nil:
+			v.AddError("email", "a user with this email already exists")
+			h.FailedValidationResponse(w, r, v.Errors)
+			return
+		case !errors.Is(err, repository.ErrRecordNotFound):
+			h.ServerErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	err = h.models.Users.Update(user)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			v.AddError("login", "a user with this login already exists")
+			h.FailedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, repository.ErrEditConflict):
+			h.EditConflictResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if emailChanged {
+		// a code sent to an address asked for before must not confirm the new one
+		err = h.models.Tokens.DeleteAllForUser(repository.ScopeEmailChange, user.ID)
+		if err != nil {
+			h.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		token, err := h.models.Tokens.New(user.ID, 8*time.Hour, repository.ScopeEmailChange)
+		if err != nil {
+			h.ServerErrorResponse(w, r, err)
+			return
+		}
+
+		email := *user.PendingEmail
+		h.service.Background(func() {
+			tmplData := map[string]interface{}{
+				"confirmationCode": token.Plaintext,
+				"Login":            user.Login,
+			}
+			err := h.service.Mailer.SendEmail(email, "email_change.tmpl", tmplData)
+			if err != nil {
+				h.service.Logger.Error(err)
+			}
+		})
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"user": user}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+type ConfirmEmailInput struct {
+	TokenPlainText string `json:"token"`
+}
+
+// ConfirmEmailHandler replaces the email of the user with the pending one
+//
+// @Summary      Email confirmation
+// @Description  Confirms the new email of the user by the code sent to it and makes it the email of the account.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        body  body     ConfirmEmailInput  true  "Email confirmation input"
+// @Success      200  {object}  UserResp  "Successfully changed email"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      409  {object}  ErrorResponse "Edit conflict"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/email [put]
+func (h *Handler) ConfirmEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var in ConfirmEmailInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if repository.ValidateTokenPlaintext(v, in.TokenPlainText); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	user, err := h.models.Users.GetForToken(repository.ScopeEmailChange, in.TokenPlainText)
+	if err == nil && user.PendingEmail == nil {
+		err = repository.ErrRecordNotFound
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			v.AddError("token", "invalid or expired confirmation token")
+			h.FailedValidationResponse(w, r, v.Errors)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	user.Email = *user.PendingEmail
+	user.PendingEmail = nil
+
+	err = h.models.Users.Update(user)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			v.AddError("email", "a user with this email already exists")
+			h.FailedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, repository.ErrEditConflict):
+			h.EditConflictResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = h.models.Tokens.DeleteAllForUser(repository.ScopeEmailChange, user.ID)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"user": user}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// ChangePasswordHandler changes the password of the current user
+//
+// @Summary      Change my password
+// @Description  Changes the password of the current user, who has to send the current one.
+// @Description  Every authentication token of the user is revoked, so the user has to sign in again.
+// @Description  Password reset and email change codes sent before are revoked as well.
+// @Tags         users
+// @Accept       json
+// @Param        body  body     ChangePasswordInput  true  "Password change input"
+// @Security Bearer
+// @Success      204  "Successfully changed password"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      401  {object}  ErrorResponse "Authentication required"
+// @Failure      409  {object}  ErrorResponse "Edit conflict"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/me/password [put]
+func (h *Handler) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	user := auth.ContextGetUser(r)
+
+	var in ChangePasswordInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	v.Check(in.CurrentPassword != "", "current_password", "must be provided")
+	if models.ValidatePasswordPlaintext(v, in.NewPassword); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	match, err := user.Password.Matches(in.CurrentPassword)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+	if !match {
+		v.AddError("current_password", "does not match")
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = user.Password.Set(in.NewPassword)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = h.models.Users.Update(user)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEditConflict):
+			h.EditConflictResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	// a reset or email change code sent before must not outlive the old password
+	for _, scope := range []string{repository.ScopeAuthentication, repository.ScopePasswordReset, repository.ScopeEmailChange} {
+		err = h.models.Tokens.DeleteAllForUser(scope, user.ID)
+		if err != nil {
+			h.ServerErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	err = helpers.WriteJSON(w, http.StatusNoContent, nil, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+type DeleteUserInput struct {
+	CurrentPassword string `json:"current_password"`
+}
+
+// DeleteCurrentUserHandler deletes the account of the current user
+//
+// @Summary      Delete my account
+// @Description  Deletes the current user together with all their tokens. The pastes they own are kept without an owner.
+// @Description  The current password is required, so a stolen token is not enough to delete the account.
+// @Tags         users
+// @Accept       json
+// @Param        body  body     DeleteUserInput  true  "Account deletion input"
+// @Security Bearer
+// @Success      204  "Successfully deleted account"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      401  {object}  ErrorResponse "Authentication required"
+// @Failure      403  {object}  ErrorResponse "The current password does not match"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/me [delete]
+func (h *Handler) DeleteCurrentUserHandler(w http.ResponseWriter, r *http.Request) {
+	user := auth.ContextGetUser(r)
+
+	var in DeleteUserInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if v.Check(in.CurrentPassword != "", "current_password", "must be provided"); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	match, err := user.Password.Matches(in.CurrentPassword)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+	if !match {
+		h.InvalidCurrentPasswordResponse(w, r)
+		return
+	}
+
+	err = h.models.Users.Delete(user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.NotFoundResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusNoContent, nil, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
+
+type ResetPasswordInput struct {
+	Password       string `json:"password"`
+	TokenPlainText string `json:"token"`
+}
+
+// ResetPasswordHandler sets a new password by a password reset token
+//
+// @Summary      Password reset
+// @Description  Sets the new password of the user by the code from the password reset email.
+// @Description  Every token of the user is revoked, so the user has to sign in again on all devices.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        body  body     ResetPasswordInput  true  "Password reset input"
+// @Success      200  {object}  MessageResp  "Successfully reset password"
+// @Failure      400  {object}  ErrorResponse "Bad request"
+// @Failure      409  {object}  ErrorResponse "Edit conflict"
+// @Failure      422  {object}  ErrorResponse "Unprocessable data"
+// @Failure 429 {object} ErrorResponse "Too many requests, rate limit exceeded"
+// @Failure      500  {object}  ErrorResponse "Internal server error"
+// @Router       /api/v1/users/password [put]
+func (h *Handler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var in ResetPasswordInput
+
+	err := helpers.ReadJSON(w, r, &in)
+	if err != nil {
+		h.BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	models.ValidatePasswordPlaintext(v, in.Password)
+	if repository.ValidateTokenPlaintext(v, in.TokenPlainText); !v.Valid() {
+		h.FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	user, err := h.models.Users.GetForToken(repository.ScopePasswordReset, in.TokenPlainText)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			v.AddError("token", "invalid or expired password reset token")
+			h.FailedValidationResponse(w, r, v.Errors)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = user.Password.Set(in.Password)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = h.models.Users.Update(user)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEditConflict):
+			h.EditConflictResponse(w, r)
+		default:
+			h.ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = h.models.Tokens.DeleteAll(user.ID)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	err = helpers.WriteJSON(w, http.StatusOK, helpers.Envelope{"message": "your password was successfully reset"}, nil)
+	if err != nil {
+		h.ServerErrorResponse(w, r, err)
+	}
+}
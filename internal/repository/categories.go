@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"pasteAPI/internal/repository/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCategoryInUse     = errors.New("category is in use")
+	ErrTooManyCategories = errors.New("too many categories")
+	ErrDuplicateCategory = errors.New("duplicate category name")
+)
+
+const categoriesCacheMaxAge = time.Minute
+
+// CategoryModel keeps the categories in memory, since they are needed to validate every
+// paste. The cache is dropped on every change and reloaded after categoriesCacheMaxAge
+// to pick up changes made through other instances. The generation is bumped on every change,
+// so a reload which started before the change does not store what it read.
+type CategoryModel struct {
+	DB *sql.DB
+
+	mu         sync.RWMutex
+	cache      models.Categories
+	cachedAt   time.Time
+	generation uint64
+}
+
+func (m *CategoryModel) Insert(c *models.Category) error {
+	query := `
+		INSERT INTO categories (name)
+		VALUES (TRIM($1))
+		RETURNING id, name`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, c.Name).Scan(&c.ID, &c.Name)
+	if err != nil {
+		return categoryError(err)
+	}
+
+	m.invalidate()
+
+	return nil
+}
+
+// GetAll returns the categories ordered by ID from the cache, loading them if needed.
+func (m *CategoryModel) GetAll() (models.Categories, error) {
+	m.mu.RLock()
+	categories, cachedAt, generation := m.cache, m.cachedAt, m.generation
+	m.mu.RUnlock()
+
+	if categories != nil && time.Since(cachedAt) < categoriesCacheMaxAge {
+		return categories, nil
+	}
+
+	query := `
+		SELECT id, name
+		FROM categories
+		ORDER BY id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories = make(models.Categories, 0)
+	for rows.Next() {
+		var category models.Category
+
+		err = rows.Scan(&category.ID, &category.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		categories = append(categories, &category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if m.generation == generation {
+		m.cache, m.cachedAt = categories, time.Now()
+	}
+	m.mu.Unlock()
+
+	return categories, nil
+}
+
+func (m *CategoryModel) Update(c *models.Category) error {
+	query := `
+		UPDATE categories
+		SET name = TRIM($1)
+		WHERE id = $2
+		RETURNING name`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, c.Name, c.ID).Scan(&c.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return categoryError(err)
+		}
+	}
+
+	m.invalidate()
+
+	return nil
+}
+
+// Delete deletes the category unless there are pastes in it, which the foreign key of
+// pastes guarantees even against pastes created at the same time.
+func (m *CategoryModel) Delete(id uint8) error {
+	query := `
+		DELETE FROM categories
+		WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return categoryError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+
+	m.invalidate()
+
+	return nil
+}
+
+func (m *CategoryModel) invalidate() {
+	m.mu.Lock()
+	m.cache = nil
+	m.generation++
+	m.mu.Unlock()
+}
+
+func categoryError(err error) error {
+	switch {
+	case strings.HasPrefix(err.Error(), `pq: duplicate key value`):
+		return ErrDuplicateCategory
+	case strings.Contains(err.Error(), `categories_id_check`):
+		return ErrTooManyCategories
+	case strings.Contains(err.Error(), `pastes_category_fkey`):
+		return ErrCategoryInUse
+	default:
+		return err
+	}
+}
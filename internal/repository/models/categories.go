@@ -1,25 +1,33 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"pasteAPI/pkg/validator"
+)
 
-type Categories []string
-
-var CategoriesList Categories = []string{
-	"Sport",
-	"Home",
-	"Work",
+type Category struct {
+	ID   uint8  `json:"id"`
+	Name string `json:"name"`
 }
 
+// Categories is the set of categories ordered by ID.
+type Categories []*Category
+
 func (c Categories) GetCategory(categoryId uint8) (string, error) {
-	if !c.IsValidCategory(categoryId) {
-		return "", errors.New("category ID is out of range")
+	for _, category := range c {
+		if category.ID == categoryId {
+			return category.Name, nil
+		}
 	}
-	return c[categoryId-1], nil
+	return "", errors.New("no category with such ID")
 }
 
 func (c Categories) IsValidCategory(categoryId uint8) bool {
-	if categoryId == 0 || int(categoryId) > len(c) {
-		return false
-	}
-	return true
+	_, err := c.GetCategory(categoryId)
+	return err == nil
+}
+
+func ValidateCategory(v *validator.Validator, c *Category) {
+	v.Check(c.Name != "", "name", "must be provided")
+	v.Check(len(c.Name) <= 64, "name", "must not be more than 64 bytes long")
 }
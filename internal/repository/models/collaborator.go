@@ -0,0 +1,22 @@
+package models
+
+import (
+	"pasteAPI/pkg/validator"
+	"time"
+)
+
+const (
+	RoleRead  = "read"
+	RoleWrite = "write"
+)
+
+type Collaborator struct {
+	UserID    int64     `json:"user_id"`
+	Login     string    `json:"login"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ValidateRole(v *validator.Validator, role string) {
+	v.Check(validator.In(role, RoleRead, RoleWrite), "role", "must be either read or write")
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var decimalRX = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?(e[-+]?[0-9]+)?$`)
+
+// Cursor points at a paste of a listing sorted by Sort. Value is the sort key of the
+// paste in the form Postgres parses it, ID breaks ties between equal keys.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"i"`
+}
+
+func NewCursor(sort string, p *Paste) Cursor {
+	c := Cursor{Sort: sort, ID: p.Id}
+
+	switch strings.TrimPrefix(sort, "-") {
+	case "id":
+		c.Value = strconv.FormatInt(p.Id, 10)
+	case "title":
+		c.Value = p.Title
+	case "created_at":
+		c.Value = p.CreatedAt.Format(time.RFC3339Nano)
+	case "expires_at":
+		c.Value = p.ExpiresAt.Format(time.RFC3339Nano)
+	case "rank":
+		c.Value = strconv.FormatFloat(float64(p.Rank), 'g', -1, 32)
+	}
+
+	return c
+}
+
+// CursorKeySize is the size of the key cursors are sealed with.
+const CursorKeySize = 32
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorCodec seals cursors with AES-GCM, so clients can neither read the paste IDs in
+// them nor forge them. Cursors sealed with another key are rejected as invalid.
+type CursorCodec struct {
+	aead cipher.AEAD
+}
+
+func NewCursorCodec(key []byte) (*CursorCodec, error) {
+	if len(key) != CursorKeySize {
+		return nil, fmt.Errorf("cursor key must be %d bytes long", CursorKeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CursorCodec{aead: aead}, nil
+}
+
+// Encode returns the opaque form of the cursor given to clients.
+func (cc *CursorCodec) Encode(c Cursor) (string, error) {
+	js, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, cc.aead.NonceSize(), cc.aead.NonceSize()+len(js)+cc.aead.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cc.aead.Seal(nonce, nonce, js, nil)), nil
+}
+
+// Decode opens the cursor made by Encode.
+func (cc *CursorCodec) Decode(s string) (*Cursor, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(sealed) < cc.aead.NonceSize() {
+		return nil, ErrInvalidCursor
+	}
+
+	nonce, sealed := sealed[:cc.aead.NonceSize()], sealed[cc.aead.NonceSize():]
+	js, err := cc.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(js, &c); err != nil || c.ID <= 0 || !c.validValue() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// validValue reports whether the value can be compared with the sort column, so a forged
+// cursor does not fail the query.
+func (c Cursor) validValue() bool {
+	var err error
+
+	switch strings.TrimPrefix(c.Sort, "-") {
+	case "id":
+		_, err = strconv.ParseInt(c.Value, 10, 64)
+	case "title":
+		return !strings.ContainsRune(c.Value, 0)
+	case "created_at", "expires_at":
+		_, err = time.Parse(time.RFC3339Nano, c.Value)
+	case "rank":
+		// ParseFloat also takes hexadecimal, infinite and NaN values Postgres would not compare
+		return decimalRX.MatchString(c.Value)
+	default:
+		return false
+	}
+
+	return err == nil
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cc := newTestCodec(t, 1)
+
+	p := &Paste{
+		Id:        42,
+		Title:     "Hello, мир \"quoted\"",
+		CreatedAt: time.Date(2024, 5, 1, 12, 30, 15, 123456789, time.UTC),
+		ExpiresAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.FixedZone("", 3*60*60)),
+		Rank:      0.0000123,
+	}
+
+	for _, sort := range []string{"id", "-id", "title", "-title", "created_at", "-created_at", "expires_at", "-expires_at", "rank", "-rank"} {
+		t.Run(sort, func(t *testing.T) {
+			want := NewCursor(sort, p)
+
+			encoded, err := cc.Encode(want)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			raw, err := base64.RawURLEncoding.DecodeString(encoded)
+			if err != nil {
+				t.Fatalf("Encode() = %q, not base64url: %v", encoded, err)
+			}
+			if bytes.Contains(raw, []byte(`"i":42`)) {
+				t.Errorf("Encode() = %q, reveals the paste ID", encoded)
+			}
+
+			got, err := cc.Decode(encoded)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if *got != want {
+				t.Errorf("Decode() = %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestCursorDecodeTampered(t *testing.T) {
+	cc := newTestCodec(t, 1)
+
+	encoded, err := cc.Encode(Cursor{Sort: "id", Value: "1", ID: 1})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	sealed, _ := base64.RawURLEncoding.DecodeString(encoded)
+
+	flipped := bytes.Clone(sealed)
+	flipped[len(flipped)-1] ^= 1
+
+	tests := []struct {
+		name   string
+		codec  *CursorCodec
+		cursor string
+	}{
+		{name: "empty", codec: cc, cursor: ""},
+		{name: "not base64", codec: cc, cursor: "!!!"},
+		{name: "shorter than nonce", codec: cc, cursor: base64.RawURLEncoding.EncodeToString(sealed[:4])},
+		{name: "unsealed JSON", codec: cc, cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"s":"id","v":"1","i":1}`))},
+		{name: "flipped bit", codec: cc, cursor: base64.RawURLEncoding.EncodeToString(flipped)},
+		{name: "truncated", codec: cc, cursor: base64.RawURLEncoding.EncodeToString(sealed[:len(sealed)-1])},
+		{name: "another key", codec: newTestCodec(t, 2), cursor: encoded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, err := tt.codec.Decode(tt.cursor); err == nil {
+				t.Errorf("Decode() = %+v, want an error", *c)
+			}
+		})
+	}
+}
+
+func TestCursorDecodeInvalidContent(t *testing.T) {
+	cc := newTestCodec(t, 1)
+
+	tests := []struct {
+		name string
+		js   string
+	}{
+		{name: "not JSON", js: `garbage`},
+		{name: "JSON array", js: `[1,2]`},
+		{name: "wrong field type", js: `{"s":"id","v":1,"i":1}`},
+		{name: "missing ID", js: `{"s":"id","v":"1"}`},
+		{name: "negative ID", js: `{"s":"id","v":"1","i":-1}`},
+		{name: "unknown sort", js: `{"s":"password","v":"x","i":1}`},
+		{name: "empty sort", js: `{"v":"1","i":1}`},
+		{name: "non-numeric id", js: `{"s":"id","v":"1; DROP TABLE pastes","i":1}`},
+		{name: "id out of range", js: `{"s":"id","v":"99999999999999999999","i":1}`},
+		{name: "NUL in title", js: `{"s":"title","v":"a\u0000b","i":1}`},
+		{name: "bad time", js: `{"s":"-created_at","v":"yesterday","i":1}`},
+		{name: "time without zone", js: `{"s":"expires_at","v":"2024-05-01T12:30:15","i":1}`},
+		{name: "hexadecimal rank", js: `{"s":"rank","v":"0x1p-2","i":1}`},
+		{name: "infinite rank", js: `{"s":"-rank","v":"Inf","i":1}`},
+		{name: "NaN rank", js: `{"s":"rank","v":"NaN","i":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, err := cc.Decode(seal(t, cc, tt.js)); err == nil {
+				t.Errorf("Decode() = %+v, want an error", *c)
+			}
+		})
+	}
+}
+
+func TestCursorDecodeValidContent(t *testing.T) {
+	cc := newTestCodec(t, 1)
+
+	tests := []struct {
+		name string
+		js   string
+		want Cursor
+	}{
+		{name: "empty title", js: `{"s":"title","v":"","i":7}`, want: Cursor{Sort: "title", ID: 7}},
+		{name: "negative id value", js: `{"s":"-id","v":"-3","i":7}`, want: Cursor{Sort: "-id", Value: "-3", ID: 7}},
+		{name: "rank with exponent", js: `{"s":"rank","v":"1.5e-05","i":7}`, want: Cursor{Sort: "rank", Value: "1.5e-05", ID: 7}},
+		{name: "unknown fields", js: `{"s":"id","v":"1","i":7,"x":true}`, want: Cursor{Sort: "id", Value: "1", ID: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cc.Decode(seal(t, cc, tt.js))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Decode() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCursorCodecKeySize(t *testing.T) {
+	for _, size := range []int{0, 16, CursorKeySize - 1, CursorKeySize + 1} {
+		if _, err := NewCursorCodec(make([]byte, size)); err == nil {
+			t.Errorf("NewCursorCodec() with a %d-byte key, want an error", size)
+		}
+	}
+}
+
+func newTestCodec(t *testing.T, fill byte) *CursorCodec {
+	t.Helper()
+
+	cc, err := NewCursorCodec(bytes.Repeat([]byte{fill}, CursorKeySize))
+	if err != nil {
+		t.Fatalf("NewCursorCodec() error = %v", err)
+	}
+	return cc
+}
+
+// seal seals arbitrary JSON the way Encode seals a cursor.
+func seal(t *testing.T, cc *CursorCodec, js string) string {
+	t.Helper()
+
+	nonce := make([]byte, cc.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(cc.aead.Seal(nonce, nonce, []byte(js), nil))
+}
@@ -5,11 +5,18 @@ import (
 	"strings"
 )
 
+// Filters hold the sort and the paging of a listing. In the keyset mode the page
+// starts after Cursor, or ends before it if Backward is set, instead of being
+// found by its number. Without a cursor the keyset page is the first one.
 type Filters struct {
 	Page         uint32
 	PageSize     uint32
 	Sort         string
 	SortSafelist []string
+	Keyset       bool
+	Cursor       *Cursor
+	Backward     bool
+	Count        bool
 }
 
 func ValidateFilters(v *validator.Validator, f Filters) {
@@ -20,6 +27,9 @@ func ValidateFilters(v *validator.Validator, f Filters) {
 	v.Check(f.PageSize <= 100, "page_size", "must be a maximum of 100")
 	// Check that the sort parameter matches a value in the safelist.
 	v.Check(validator.In(f.Sort, f.SortSafelist...), "sort", "invalid sort value")
+	if f.Cursor != nil {
+		v.Check(f.Cursor.Sort == f.Sort, "cursor", "does not match the sort")
+	}
 }
 
 func (f Filters) SortColumn() string {
@@ -9,6 +9,8 @@ type Metadata struct {
 	FirstPage    uint32 `json:"first_page,omitempty"`
 	LastPage     uint32 `json:"last_page,omitempty"`
 	TotalRecords uint32 `json:"total_records,omitempty"`
+	NextCursor   string `json:"next_cursor,omitempty"`
+	PrevCursor   string `json:"prev_cursor,omitempty"`
 }
 
 func CalculateMetadata(totalRecords, page, pageSize uint32) Metadata {
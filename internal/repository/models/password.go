@@ -20,6 +20,12 @@ func (p *password) Set(plaintextPassword string) error {
 	return nil
 }
 
+// Clear removes the password, so that it is stored as NULL.
+func (p *password) Clear() {
+	p.Plaintext = nil
+	p.Hash = nil
+}
+
 func (p *password) Matches(plaintextPassword string) (bool, error) {
 	err := bcrypt.CompareHashAndPassword(p.Hash, []byte(plaintextPassword))
 	if err != nil {
@@ -1,27 +1,153 @@
 package models
 
 import (
+	"crypto/rand"
+	"fmt"
+	"hash/fnv"
+	"pasteAPI/pkg/langdetect"
+	"pasteAPI/pkg/textlang"
 	"pasteAPI/pkg/validator"
+	"strings"
 	"time"
 )
 
+const slugAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MaxSlugLength is the length of the slug column and the longest slug accepted in URLs.
+const MaxSlugLength = 32
+
+// Unlisted pastes are readable by anyone who has the link but are left out of listings,
+// private pastes are readable only by the owner and collaborators.
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private"
+)
+
+// Roles of a user in the pastes listed for them: the owner or a collaborator.
+const (
+	PasteRoleOwner        = "owner"
+	PasteRoleCollaborator = "collaborator"
+)
+
+// Markdown pastes can be rendered to HTML, plain ones are shown as they are.
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+// Paste is a text post. Language is the programming language of the text and
+// NaturalLanguage is the human one it is searched in. Owner holds the ID of the user who controls the paste
+// and is nil for pastes created anonymously. Protected pastes are hidden behind
+// a password. Pastes with MaxViews are deleted after that many reads, burn after
+// read pastes are limited to a single one. Text of both is left empty in listings.
+// Snippet and Rank are set in listings searched by a query only, Role in listings of a user only.
 type Paste struct {
-	Id        uint16    `json:"id"`
-	Title     string    `json:"title"`
-	Category  uint8     `json:"category,omitempty"`
-	Text      string    `json:"text"`
-	CreatedAt time.Time `json:"created_at"`
-	ExpiresAt time.Time `json:"expires_at"`
-	Minutes   int32     `json:"-"`
-	Version   uint32    `json:"version"`
+	Id              int64     `json:"-"`
+	Slug            string    `json:"slug"`
+	Title           string    `json:"title"`
+	Category        uint8     `json:"category,omitempty"`
+	CategoryName    string    `json:"category_name,omitempty"`
+	Text            string    `json:"text,omitempty"`
+	Language        string    `json:"language"`
+	Format          string    `json:"format"`
+	NaturalLanguage string    `json:"natural_language"`
+	Tags            []string  `json:"tags"`
+	Snippet         string    `json:"snippet,omitempty"`
+	Rank            float32   `json:"-"`
+	Role            string    `json:"role,omitempty"`
+	Owner           *int64    `json:"owner"`
+	Visibility      string    `json:"visibility"`
+	Password        password  `json:"-"`
+	Protected       bool      `json:"protected"`
+	BurnAfterRead   bool      `json:"burn_after_read"`
+	MaxViews        *int32    `json:"max_views,omitempty"`
+	Views           int32     `json:"views,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	Minutes         int32     `json:"-"`
+	Version         uint32    `json:"version"`
 }
 
-func ValidatePaste(v *validator.Validator, p *Paste) {
+// PasteSearch holds the criteria pastes are listed by. Zero values match every paste.
+// Query is matched against both the title and the text, Title against the title only.
+// NaturalLanguage narrows the pastes and the query parsing to a single language.
+// UserID narrows the pastes to the ones the user owns or collaborates on, or only
+// to the ones in Role. Unlisted and private pastes are included if AllVisibilities is set.
+type PasteSearch struct {
+	UserID          int64
+	Role            string
+	AllVisibilities bool
+	Query           string
+	NaturalLanguage string
+	Title           string
+	Category        uint8
+	Language        string
+	Tags            []string
+	TagsMatch       string
+}
+
+// ETag returns the strong entity tag of the paste. It changes with every new version and
+// with the state changed without a new version: the owner and what is joined from other
+// tables, such as a renamed category.
+func (p *Paste) ETag() string {
+	var owner int64
+	if p.Owner != nil {
+		owner = *p.Owner
+	}
+
+	joined := fnv.New32a()
+	fmt.Fprintf(joined, "%d\x00%s\x00%s", owner, p.CategoryName, strings.Join(p.Tags, ","))
+	return fmt.Sprintf(`"%s-%d-%08x"`, p.Slug, p.Version, joined.Sum32())
+}
+
+func ValidatePaste(v *validator.Validator, p *Paste, categories Categories) {
 	v.Check(p.Title != "", "title", "must be provided")
 	v.Check(len(p.Title) <= 255, "title", "must not be more than 500 bytes long")
 
-	v.Check(CategoriesList.IsValidCategory(p.Category), "category", "no such category")
+	v.Check(categories.IsValidCategory(p.Category), "category", "no such category")
 
 	v.Check(p.Text != "", "text", "must be provided")
 	v.Check(len(p.Title) <= 500, "title", "must not be more than 500 bytes long")
+
+	v.Check(langdetect.IsKnown(p.Language), "language", "unknown language")
+	v.Check(validator.In(p.Format, FormatPlain, FormatMarkdown), "format", "must be plain or markdown")
+	v.Check(textlang.IsKnown(p.NaturalLanguage), "natural_language", "unknown natural language")
+
+	ValidateTags(v, p.Tags)
+
+	v.Check(validator.In(p.Visibility, VisibilityPublic, VisibilityUnlisted, VisibilityPrivate), "visibility", "must be public, unlisted or private")
+	v.Check(p.Visibility != VisibilityPrivate || p.Owner != nil, "visibility", "anonymous pastes can not be private")
+
+	if p.MaxViews != nil {
+		v.Check(*p.MaxViews > 0, "max_views", "must be greater than zero")
+		v.Check(*p.MaxViews <= 1_000_000, "max_views", "must be a maximum of 1 million")
+		v.Check(!p.BurnAfterRead || *p.MaxViews == 1, "max_views", "burn after read pastes can be read only once")
+	}
+}
+
+// GenerateSlug returns a random base62 string of the given length.
+func GenerateSlug(length int) (string, error) {
+	slug := make([]byte, 0, length)
+	randomBytes := make([]byte, length)
+
+	for len(slug) < length {
+		_, err := rand.Read(randomBytes)
+		if err != nil {
+			return "", err
+		}
+		for _, b := range randomBytes {
+			// skip bytes which would bias the modulo towards the first symbols
+			if int(b) >= 256-256%len(slugAlphabet) {
+				continue
+			}
+			slug = append(slug, slugAlphabet[int(b)%len(slugAlphabet)])
+			if len(slug) == length {
+				break
+			}
+		}
+	}
+
+	return string(slug), nil
 }
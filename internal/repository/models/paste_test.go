@@ -0,0 +1,54 @@
+package models
+
+import (
+	"pasteAPI/pkg/validator"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSlug(t *testing.T) {
+	for _, length := range []int{1, 8, 12, MaxSlugLength} {
+		seen := make(map[string]bool)
+
+		for i := 0; i < 100; i++ {
+			slug, err := GenerateSlug(length)
+			if err != nil {
+				t.Fatalf("GenerateSlug(%d) error = %v", length, err)
+			}
+
+			if len(slug) != length {
+				t.Errorf("GenerateSlug(%d) = %q, want %d characters", length, slug, length)
+			}
+			if strings.Trim(slug, slugAlphabet) != "" {
+				t.Errorf("GenerateSlug(%d) = %q, want base62 characters only", length, slug)
+			}
+			if !validator.Matches(slug, validator.SlugRX) {
+				t.Errorf("GenerateSlug(%d) = %q, rejected by SlugRX", length, slug)
+			}
+
+			seen[slug] = true
+		}
+
+		// 62^8 slugs make a collision among a hundred practically impossible
+		if length >= 8 && len(seen) != 100 {
+			t.Errorf("GenerateSlug(%d) repeated slugs: %d unique of 100", length, len(seen))
+		}
+	}
+}
+
+func TestGenerateSlugUsesWholeAlphabet(t *testing.T) {
+	seen := make(map[rune]bool)
+	for i := 0; i < 100; i++ {
+		slug, err := GenerateSlug(MaxSlugLength)
+		if err != nil {
+			t.Fatalf("GenerateSlug() error = %v", err)
+		}
+		for _, r := range slug {
+			seen[r] = true
+		}
+	}
+
+	if len(seen) != len(slugAlphabet) {
+		t.Errorf("GenerateSlug() used %d of %d symbols", len(seen), len(slugAlphabet))
+	}
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Revision is a snapshot of the paste content at the given version.
+type Revision struct {
+	Version   uint32    `json:"version"`
+	Title     string    `json:"title"`
+	Text      string    `json:"text,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
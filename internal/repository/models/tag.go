@@ -0,0 +1,44 @@
+package models
+
+import (
+	"pasteAPI/pkg/validator"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaxTags is the maximum number of tags of one paste.
+const MaxTags = 10
+
+var TagRX = regexp.MustCompile(`^[\p{L}\p{N}_+#.-]{1,32}$`)
+
+// Any matches pastes with at least one of the requested tags, all only those with every one.
+const (
+	TagsMatchAny = "any"
+	TagsMatchAll = "all"
+)
+
+// TagCount is a tag with the number of public pastes it is on.
+type TagCount struct {
+	Name   string `json:"name"`
+	Pastes int64  `json:"pastes"`
+}
+
+// NormalizeTags trims and lowercases the tags and sorts them. Duplicates are kept,
+// so that ValidateTags can report them.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(tag)))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func ValidateTags(v *validator.Validator, tags []string) {
+	v.Check(len(tags) <= MaxTags, "tags", "must not contain more than 10 tags")
+	v.Check(validator.Unique(tags), "tags", "must not contain duplicate values")
+	for _, tag := range tags {
+		v.Check(validator.Matches(tag, TagRX), "tags", "must contain letters, digits and _+#.- only, up to 32 characters each")
+	}
+}
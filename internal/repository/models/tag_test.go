@@ -0,0 +1,64 @@
+package models
+
+import (
+	"pasteAPI/pkg/validator"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "nil", tags: nil, want: []string{}},
+		{name: "trimmed and lowercased", tags: []string{" Go ", "SQL"}, want: []string{"go", "sql"}},
+		{name: "sorted", tags: []string{"b", "c", "a"}, want: []string{"a", "b", "c"}},
+		{name: "duplicates kept", tags: []string{"Go", "go "}, want: []string{"go", "go"}},
+		{name: "unicode", tags: []string{"Привет"}, want: []string{"привет"}},
+		{name: "empty kept", tags: []string{" "}, want: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeTags(tt.tags)
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags(%q) = %#v, want %#v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		tags  []string
+		valid bool
+	}{
+		{name: "none", tags: []string{}, valid: true},
+		{name: "simple", tags: []string{"go", "sql"}, valid: true},
+		{name: "symbols", tags: []string{"c++", "c#", "node.js", "snake_case", "x-y"}, valid: true},
+		{name: "unicode letters", tags: []string{"привет", "日本"}, valid: true},
+		{name: "longest", tags: []string{strings.Repeat("a", 32)}, valid: true},
+		{name: "too long", tags: []string{strings.Repeat("a", 33)}, valid: false},
+		{name: "empty", tags: []string{""}, valid: false},
+		{name: "space", tags: []string{"two words"}, valid: false},
+		{name: "comma", tags: []string{"a,b"}, valid: false},
+		{name: "markup", tags: []string{"<b>"}, valid: false},
+		{name: "duplicates", tags: []string{"go", "go"}, valid: false},
+		{name: "maximum", tags: strings.Split("a b c d e f g h i j", " "), valid: true},
+		{name: "too many", tags: strings.Split("a b c d e f g h i j k", " "), valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+			ValidateTags(v, tt.tags)
+			if v.Valid() != tt.valid {
+				t.Errorf("ValidateTags(%q) valid = %v, want %v, errors %v", tt.tags, v.Valid(), tt.valid, v.Errors)
+			}
+		})
+	}
+}
@@ -7,12 +7,32 @@ import (
 	"time"
 )
 
+// Token is a secret sent to a user. Authentication tokens also record the client
+// they were issued to, so the user can tell their sessions apart.
 type Token struct {
 	Plaintext string    `json:"token"`
 	Hash      []byte    `json:"-"`
 	UserID    int64     `json:"-"`
 	Expiry    time.Time `json:"expiry"`
 	Scope     string    `json:"-"`
+	UserAgent string    `json:"-"`
+	IP        string    `json:"-"`
+}
+
+// MaxUserAgentLength is the number of characters of the user agent kept for a session.
+const MaxUserAgentLength = 256
+
+// Session is an authentication token as the user sees it. LastUsedAt is updated at most
+// once a minute and is nil until the token is used. UserAgent and IP are of the client the
+// token was created for. Current marks the token of the request.
+type Session struct {
+	ID         int64      `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Expiry     time.Time  `json:"expiry"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	Current    bool       `json:"current"`
 }
 
 func GenerateToken(userID int64, ttl time.Duration, scope string) (*Token, error) {
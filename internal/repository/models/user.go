@@ -7,14 +7,18 @@ import (
 
 var AnonymousUser = &User{}
 
+// User is an account. PendingEmail is the new address the user has asked to change
+// the email to, which replaces Email once it is verified.
 type User struct {
-	ID        int64     `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	Login     string    `json:"login"`
-	Email     string    `json:"email"`
-	Password  password  `json:"-"`
-	Activated bool      `json:"activated"`
-	Version   int       `json:"-"`
+	ID           int64     `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Login        string    `json:"login"`
+	Email        string    `json:"email"`
+	PendingEmail *string   `json:"pending_email,omitempty"`
+	Password     password  `json:"-"`
+	Activated    bool      `json:"activated"`
+	Admin        bool      `json:"admin,omitempty"`
+	Version      int       `json:"-"`
 }
 
 func (u *User) IsAnonymous() bool {
@@ -5,52 +5,188 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"html"
 	"pasteAPI/internal/repository/models"
+	"pasteAPI/pkg/textlang"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+// pasteTagsColumn selects the sorted tag names of the paste as an array.
+const pasteTagsColumn = `ARRAY(
+			SELECT tags.name::text
+			FROM paste_tags
+			INNER JOIN tags ON tags.id = paste_tags.tag_id
+			WHERE paste_tags.paste_id = pastes.id
+			ORDER BY tags.name)`
+
+// maxSlugAttempts limits how many times Create regenerates a slug after a collision.
+const maxSlugAttempts = 5
+
 type PasteModel struct {
-	DB *sql.DB
+	DB         *sql.DB
+	SlugLength int
+	Cursors    *models.CursorCodec
 }
 
 // === CRUD OPERATIONS ===
 
 func (m *PasteModel) Create(p *models.Paste) error {
 	query := `
-		INSERT INTO pastes (title, category, text, expires_at)
-		VALUES (TRIM($1), $2, TRIM($3), NOW() + interval '1 minute' * $4)
-		RETURNING id, created_at, expires_at`
+		WITH paste AS (
+			INSERT INTO pastes (slug, title, category, text, language, format, natural_language, owner_id, visibility, password_hash, burn_after_read, max_views, expires_at)
+			VALUES ($1, TRIM($2), $3, TRIM($4), $5, $6, $7, $8, $9, $10, $11, $12, NOW() + interval '1 minute' * $13)
+			RETURNING id, title, text, created_at, updated_at, expires_at, version
+		), revision AS (
+			INSERT INTO paste_revisions (paste_id, version, title, text, created_at)
+			SELECT id, version, title, text, created_at FROM paste
+		)
+		SELECT id, created_at, updated_at, expires_at FROM paste`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		slug, err := models.GenerateSlug(m.SlugLength)
+		if err != nil {
+			return err
+		}
+
+		args := []interface{}{slug, p.Title, p.Category, p.Text, p.Language, p.Format, p.NaturalLanguage, p.Owner, p.Visibility, p.Password.Hash, p.BurnAfterRead, p.MaxViews, p.Minutes}
+
+		err = m.create(ctx, query, args, p)
+		switch {
+		case err == nil:
+			p.Slug = slug
+			p.Protected = p.Password.Hash != nil
+			return nil
+		case strings.HasPrefix(err.Error(), `pq: duplicate key value`) && attempt < maxSlugAttempts:
+			continue
+		default:
+			return err
+		}
+	}
+}
+
+// create inserts the paste with its tags in one transaction.
+func (m *PasteModel) create(ctx context.Context, query string, args []interface{}, p *models.Paste) error {
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	args := []interface{}{p.Title, p.Category, p.Text, p.Minutes}
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&p.Id, &p.CreatedAt, &p.UpdatedAt, &p.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	if len(p.Tags) > 0 {
+		err = setPasteTags(ctx, tx, p.Id, p.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Get fetches the paste without counting it as a view.
+func (m *PasteModel) Get(slug string) (*models.Paste, error) {
+	if slug == "" {
+		return nil, ErrRecordNotFound
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
 
-	return m.DB.QueryRowContext(ctx, query, args...).Scan(&p.Id, &p.CreatedAt, &p.ExpiresAt)
+	return m.get(ctx, m.DB, slug, false)
 }
 
-func (m *PasteModel) Read(id uint16) (*models.Paste, error) {
-	if id == 0 {
+// Read fetches the paste for a reader. If the number of views is limited, the view is
+// counted and the paste is deleted after the last allowed one in the same transaction,
+// so concurrent readers can never get more views than the limit.
+func (m *PasteModel) Read(slug string) (*models.Paste, error) {
+	if slug == "" {
 		return nil, ErrRecordNotFound
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	paste, err := m.get(ctx, tx, slug, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if paste.MaxViews != nil {
+		paste.Views++
+
+		query := `UPDATE pastes SET views = views + 1 WHERE id = $1`
+		if paste.Views >= *paste.MaxViews {
+			query = `DELETE FROM pastes WHERE id = $1`
+		}
+
+		_, err = tx.ExecContext(ctx, query, paste.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return paste, nil
+}
+
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (m *PasteModel) get(ctx context.Context, q rowQuerier, slug string, forUpdate bool) (*models.Paste, error) {
 	query := `
-		SELECT id, title, category, text, created_at, expires_at, version 
+		SELECT id, slug, title, category, COALESCE((SELECT name FROM categories WHERE categories.id = pastes.category), ''), text, language, format, natural_language, owner_id, visibility, password_hash,
+		       burn_after_read, max_views, views, created_at, updated_at, expires_at, version, ` + pasteTagsColumn + `
 		FROM pastes 
-		WHERE id = $1 AND expires_at >= NOW()`
+		WHERE slug = $1 AND expires_at >= NOW()`
 
-	var paste models.Paste
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
-	defer cancel()
+	var paste models.Paste
 
-	err := m.DB.QueryRowContext(ctx, query, id).Scan(
+	err := q.QueryRowContext(ctx, query, slug).Scan(
 		&paste.Id,
+		&paste.Slug,
 		&paste.Title,
 		&paste.Category,
+		&paste.CategoryName,
 		&paste.Text,
+		&paste.Language,
+		&paste.Format,
+		&paste.NaturalLanguage,
+		&paste.Owner,
+		&paste.Visibility,
+		&paste.Password.Hash,
+		&paste.BurnAfterRead,
+		&paste.MaxViews,
+		&paste.Views,
 		&paste.CreatedAt,
+		&paste.UpdatedAt,
 		&paste.ExpiresAt,
 		&paste.Version,
+		pq.Array(&paste.Tags),
 	)
 
 	if err != nil {
@@ -62,23 +198,98 @@ func (m *PasteModel) Read(id uint16) (*models.Paste, error) {
 		}
 	}
 
+	paste.Protected = paste.Password.Hash != nil
+
 	return &paste, nil
 }
 
-func (m *PasteModel) ReadAll(title string, category uint8, filters models.Filters) ([]*models.Paste, *models.Metadata, error) {
-	query := fmt.Sprintf(`
-		SELECT COUNT(*) OVER(), id, title, category, text, created_at, expires_at, version 
-		FROM pastes 
-		WHERE expires_at >= NOW()
-		AND ($1 = '' or (to_tsvector('english', title) @@ plainto_tsquery($1)) or (to_tsvector('russian', title) @@ plainto_tsquery($1)))
+// ReadAll lists the pastes matching the search, public ones unless the search says otherwise. The pastes are paged by Filters.Page
+// or, in the keyset mode, by Filters.Cursor. The total is counted only if Filters.Count is set.
+func (m *PasteModel) ReadAll(search models.PasteSearch, filters models.Filters) ([]*models.Paste, *models.Metadata, error) {
+	from := fmt.Sprintf(`
+		FROM pastes, (SELECT %s) AS search(query)
+		WHERE expires_at >= NOW() AND (visibility = 'public' OR $9)
+		AND ($7::bigint = 0 OR pastes.id IN (
+			SELECT id FROM pastes WHERE owner_id = $7 AND $8 <> 'collaborator'
+			UNION
+			SELECT paste_id FROM write_permissions WHERE user_id = $7 AND $8 <> 'owner'
+		))
+		AND ($6 = '' OR search_vector @@ search.query)
+		AND %s
+		AND ($1 = '' or (to_tsvector('english', title) @@ plainto_tsquery('english', $1)) or (to_tsvector('russian', title) @@ plainto_tsquery('russian', $1)))
 		AND (category = $2 or $2 = 0)
-		ORDER BY %s %s, id ASC
-		LIMIT $3 OFFSET $4`, filters.SortColumn(), filters.SortDirection())
+		AND (language = $3 or $3 = '')
+		AND (cardinality($4::citext[]) = 0 OR (
+			SELECT COUNT(*)
+			FROM paste_tags
+			INNER JOIN tags ON tags.id = paste_tags.tag_id
+			WHERE paste_tags.paste_id = pastes.id AND tags.name = ANY($4::citext[])
+		) >= CASE WHEN $5 THEN cardinality($4::citext[]) ELSE 1 END)`, searchQuery(search.NaturalLanguage), searchLanguageFilter(search.NaturalLanguage))
+
+	args := []interface{}{
+		search.Title,
+		search.Category,
+		search.Language,
+		pq.Array(search.Tags),
+		search.TagsMatch == models.TagsMatchAll,
+		search.Query,
+		search.UserID,
+		search.Role,
+		search.AllVisibilities,
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
 
-	rows, err := m.DB.QueryContext(ctx, query, title, category, filters.Limit(), filters.Offset())
+	var totalRecords uint32
+	if filters.Count {
+		err := m.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+from, args...).Scan(&totalRecords)
+		if err != nil {
+			return nil, &models.Metadata{}, err
+		}
+	}
+
+	column, direction := filters.SortColumn(), filters.SortDirection()
+	limit, offset := filters.Limit(), filters.Offset()
+
+	if filters.Keyset {
+		// going back, the page is read in the reverse order and turned around afterwards
+		if filters.Backward {
+			direction = reverseDirection(direction)
+		}
+
+		if filters.Cursor != nil {
+			key := pasteSortKeys[column]
+			operator := ">"
+			if direction == "DESC" {
+				operator = "<"
+			}
+
+			from += fmt.Sprintf(`
+		AND (%s, id) %s ($%d::%s, $%d)`, key.expr, operator, len(args)+1, key.typ, len(args)+2)
+			args = append(args, filters.Cursor.Value, filters.Cursor.ID)
+		}
+
+		// one more paste tells whether there is a page after this one
+		limit, offset = filters.PageSize+1, 0
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, slug, title, category, COALESCE((SELECT name FROM categories WHERE categories.id = pastes.category), ''),
+		       CASE WHEN password_hash IS NULL AND max_views IS NULL THEN text ELSE '' END,
+		       language, format, natural_language, owner_id, visibility, password_hash IS NOT NULL, burn_after_read, max_views, views, created_at, updated_at, expires_at, version, `+pasteTagsColumn+`,
+		       CASE WHEN $6 <> '' AND password_hash IS NULL AND max_views IS NULL
+		            THEN ts_headline(natural_language, text, search.query, 'MaxFragments=2, MaxWords=20, MinWords=5, StartSel=<mark>, StopSel=</mark>')
+		            ELSE '' END,
+		       ts_rank(search_vector, search.query) AS rank,
+		       CASE WHEN $7::bigint = 0 THEN '' WHEN owner_id = $7 THEN 'owner' ELSE 'collaborator' END
+		%s
+		ORDER BY %s %s, id %s
+		LIMIT $%d OFFSET $%d`, from, column, direction, direction, len(args)+1, len(args)+2)
+
+	args = append(args, limit, offset)
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, &models.Metadata{}, err
 	}
@@ -86,25 +297,41 @@ func (m *PasteModel) ReadAll(title string, category uint8, filters models.Filter
 	defer rows.Close()
 
 	pastes := make([]*models.Paste, 0)
-	var totalRecords uint32
 
 	for rows.Next() {
 		var paste models.Paste
 
 		err := rows.Scan(
-			&totalRecords,
 			&paste.Id,
+			&paste.Slug,
 			&paste.Title,
 			&paste.Category,
+			&paste.CategoryName,
 			&paste.Text,
+			&paste.Language,
+			&paste.Format,
+			&paste.NaturalLanguage,
+			&paste.Owner,
+			&paste.Visibility,
+			&paste.Protected,
+			&paste.BurnAfterRead,
+			&paste.MaxViews,
+			&paste.Views,
 			&paste.CreatedAt,
+			&paste.UpdatedAt,
 			&paste.ExpiresAt,
 			&paste.Version,
+			pq.Array(&paste.Tags),
+			&paste.Snippet,
+			&paste.Rank,
+			&paste.Role,
 		)
 		if err != nil {
 			return nil, &models.Metadata{}, err
 		}
 
+		paste.Snippet = snippetHTML(paste.Snippet)
+
 		pastes = append(pastes, &paste)
 	}
 
@@ -112,31 +339,184 @@ func (m *PasteModel) ReadAll(title string, category uint8, filters models.Filter
 		return nil, &models.Metadata{}, err
 	}
 
-	metadata := models.CalculateMetadata(totalRecords, filters.Page, filters.PageSize)
+	if !filters.Keyset {
+		metadata := models.Metadata{CurrentPage: filters.Page, PageSize: filters.PageSize}
+		if filters.Count {
+			metadata = models.CalculateMetadata(totalRecords, filters.Page, filters.PageSize)
+		}
+		return pastes, &metadata, nil
+	}
+
+	more := len(pastes) > int(filters.PageSize)
+	if more {
+		pastes = pastes[:filters.PageSize]
+	}
+	if filters.Backward {
+		slices.Reverse(pastes)
+	}
+
+	metadata := models.Metadata{PageSize: filters.PageSize, TotalRecords: totalRecords}
+	if len(pastes) > 0 {
+		first, last := pastes[0], pastes[len(pastes)-1]
+		// the cursor itself points at a paste on the other side of the page
+		hasPrev, hasNext := filters.Cursor != nil, more
+		if filters.Backward {
+			hasPrev, hasNext = more, true
+		}
+		if hasPrev {
+			metadata.PrevCursor, err = m.Cursors.Encode(models.NewCursor(filters.Sort, first))
+			if err != nil {
+				return nil, &models.Metadata{}, err
+			}
+		}
+		if hasNext {
+			metadata.NextCursor, err = m.Cursors.Encode(models.NewCursor(filters.Sort, last))
+			if err != nil {
+				return nil, &models.Metadata{}, err
+			}
+		}
+	}
 
 	return pastes, &metadata, nil
 }
 
-func (m *PasteModel) Update(p *models.Paste) error {
+// pasteSortKeys holds the expressions and types of the columns pastes are sorted by,
+// which the keyset pagination compares the cursor against.
+var pasteSortKeys = map[string]struct{ expr, typ string }{
+	"id":         {"id", "bigint"},
+	"title":      {"title", "text"},
+	"created_at": {"created_at", "timestamptz"},
+	"expires_at": {"expires_at", "timestamptz"},
+	"rank":       {"ts_rank(search_vector, search.query)", "real"},
+}
+
+func reverseDirection(direction string) string {
+	if direction == "DESC" {
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// searchQuery returns the expression parsing the query $6 with the configuration of the
+// language. Without one, the query is parsed for every language and matches pastes in any.
+func searchQuery(language string) string {
+	if language != "" {
+		return fmt.Sprintf("websearch_to_tsquery('%s', $6)", safeTextLanguage(language))
+	}
+
+	queries := make([]string, 0, len(textlang.Names()))
+	for _, name := range textlang.Names() {
+		queries = append(queries, fmt.Sprintf("websearch_to_tsquery('%s', $6)", name))
+	}
+	return strings.Join(queries, " || ")
+}
+
+func searchLanguageFilter(language string) string {
+	if language == "" {
+		return "TRUE"
+	}
+	return fmt.Sprintf("natural_language = '%s'", safeTextLanguage(language))
+}
+
+// safeTextLanguage guards the language put into a query, as Filters.SortColumn does for sort columns.
+func safeTextLanguage(language string) string {
+	if !textlang.IsKnown(language) {
+		panic("unsafe natural language: " + language)
+	}
+	return language
+}
+
+// snippetHTML escapes the headline made by ts_headline except for the <mark> tags around the matches.
+func snippetHTML(headline string) string {
+	if headline == "" {
+		return ""
+	}
+
+	parts := strings.Split(headline, "<mark>")
+
+	var sb strings.Builder
+	sb.WriteString(html.EscapeString(parts[0]))
+	for _, part := range parts[1:] {
+		match, rest, found := strings.Cut(part, "</mark>")
+		if !found {
+			// the text itself contained the tag
+			sb.WriteString(html.EscapeString("<mark>" + part))
+			continue
+		}
+		sb.WriteString("<mark>" + html.EscapeString(match) + "</mark>" + html.EscapeString(rest))
+	}
+
+	return sb.String()
+}
+
+// Transfer makes the user the owner of the paste if it has not been changed since it was read.
+// The content is the same, so neither the version is bumped nor a revision recorded.
+func (m *PasteModel) Transfer(p *models.Paste, ownerID int64) error {
 	query := `
         UPDATE pastes
-        SET title = TRIM($1), category = $2, text = TRIM($3), expires_at = expires_at + interval '1 minute' * $4, version = version + 1
-        WHERE id = $5 AND expires_at >= NOW() AND version=$6
-        RETURNING version`
+        SET owner_id = $1, updated_at = NOW()
+        WHERE id = $2 AND version = $3 AND owner_id IS NOT DISTINCT FROM $4 AND expires_at >= NOW()
+        RETURNING updated_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, ownerID, p.Id, p.Version, p.Owner).Scan(&p.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	p.Owner = &ownerID
+
+	return nil
+}
+
+// Update saves the paste if it has not been changed since it was read and records
+// the new content as a revision.
+func (m *PasteModel) Update(p *models.Paste) error {
+	query := `
+        WITH paste AS (
+            UPDATE pastes
+            SET title = TRIM($1), category = $2, text = TRIM($3), owner_id = $4, visibility = $5, password_hash = $6,
+                language = $10, format = $11, natural_language = $12, expires_at = expires_at + interval '1 minute' * $7, updated_at = NOW(), version = version + 1
+            WHERE id = $8 AND expires_at >= NOW() AND version=$9
+            RETURNING id, title, text, updated_at, expires_at, version
+        ), revision AS (
+            INSERT INTO paste_revisions (paste_id, version, title, text, created_at)
+            SELECT id, version, title, text, updated_at FROM paste
+        )
+        SELECT updated_at, expires_at, version FROM paste`
 
 	args := []interface{}{
 		p.Title,
 		p.Category,
 		p.Text,
+		p.Owner,
+		p.Visibility,
+		p.Password.Hash,
 		p.Minutes,
 		p.Id,
 		p.Version,
+		p.Language,
+		p.Format,
+		p.NaturalLanguage,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
 
-	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&p.Version)
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&p.UpdatedAt, &p.ExpiresAt, &p.Version)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -145,18 +525,30 @@ func (m *PasteModel) Update(p *models.Paste) error {
 			return err
 		}
 	}
+
+	err = setPasteTags(ctx, tx, p.Id, p.Tags)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	p.Protected = p.Password.Hash != nil
+
 	return nil
 }
 
-func (m *PasteModel) Delete(id uint16) error {
+func (m *PasteModel) Delete(slug string) error {
 	query := `
 		DELETE FROM pastes
-		WHERE id = $1`
+		WHERE slug = $1`
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
 
-	result, err := m.DB.ExecContext(ctx, query, id)
+	result, err := m.DB.ExecContext(ctx, query, slug)
 	if err != nil {
 		return err
 	}
@@ -172,6 +564,29 @@ func (m *PasteModel) Delete(id uint16) error {
 	return nil
 }
 
+// DeleteExpired deletes up to limit expired pastes and returns how many were deleted.
+func (m *PasteModel) DeleteExpired(limit int) (int64, error) {
+	query := `
+		DELETE FROM pastes
+		WHERE id IN (
+			SELECT id
+			FROM pastes
+			WHERE expires_at < NOW()
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
 /*
 type MockPasteModel struct{}
 
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"pasteAPI/internal/repository/models"
 	"time"
 )
 
@@ -10,19 +11,140 @@ type PermissionModel struct {
 	DB *sql.DB
 }
 
-func (m *PermissionModel) SetWritePermission(userId int64, pasteId uint16) error {
+// SetPermission grants the user the role on the paste or changes the role which was granted before.
+// It reports whether the user has become a new collaborator.
+func (m *PermissionModel) SetPermission(userId, pasteId int64, role string) (bool, error) {
+	// xmax is zero only for the freshly inserted row, so it tells an insert from an update
 	query := `
-        INSERT INTO write_permissions (user_id, paste_id)
-        VALUES ($1, $2)`
+        INSERT INTO write_permissions (user_id, paste_id, role)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (paste_id, user_id) DO UPDATE SET role = EXCLUDED.role
+        RETURNING (xmax = 0)`
+
+	var inserted bool
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, userId, pasteId, role).Scan(&inserted)
+	return inserted, err
+}
+
+func (m *PermissionModel) DeletePermission(userId, pasteId int64) error {
+	query := `
+        DELETE FROM write_permissions
+        WHERE user_id = $1 AND paste_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, userId, pasteId)
+	if err != nil {
+		return err
+	}
+
+	rws, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rws == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (m *PermissionModel) GetCollaborators(pasteId int64) ([]*models.Collaborator, error) {
+	query := `
+        SELECT users.id, users.login, write_permissions.role, write_permissions.created_at
+        FROM write_permissions
+        INNER JOIN users
+        ON users.id = write_permissions.user_id
+        WHERE write_permissions.paste_id = $1
+        ORDER BY write_permissions.created_at, users.id`
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
 
-	_, err := m.DB.ExecContext(ctx, query, userId, pasteId)
-	return err
+	rows, err := m.DB.QueryContext(ctx, query, pasteId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	collaborators := make([]*models.Collaborator, 0)
+
+	for rows.Next() {
+		var collaborator models.Collaborator
+
+		err := rows.Scan(
+			&collaborator.UserID,
+			&collaborator.Login,
+			&collaborator.Role,
+			&collaborator.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		collaborators = append(collaborators, &collaborator)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return collaborators, nil
+}
+
+// GetWritePermission reports whether the user owns the paste or was granted write access to it.
+func (m *PermissionModel) GetWritePermission(userId int64, slug string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+            SELECT 1
+            FROM pastes
+            LEFT JOIN write_permissions
+            ON write_permissions.paste_id = pastes.id AND write_permissions.user_id = $1 AND write_permissions.role = 'write'
+            WHERE pastes.slug = $2 AND (pastes.owner_id = $1 OR write_permissions.user_id IS NOT NULL)
+        )`
+
+	var exists bool
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, userId, slug).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (m *PermissionModel) IsOwner(userId int64, slug string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+            SELECT 1
+            FROM pastes
+            WHERE owner_id = $1 AND slug = $2
+        )`
+
+	var exists bool
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, userId, slug).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
 }
 
-func (m *PermissionModel) GetWritePermission(userId int64, pasteId uint16) (bool, error) {
+// IsCollaborator reports whether the user was granted any role on the paste.
+func (m *PermissionModel) IsCollaborator(userId, pasteId int64) (bool, error) {
 	query := `
 		SELECT EXISTS (
             SELECT 1
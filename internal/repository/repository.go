@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"pasteAPI/internal/repository/models"
 	"time"
 )
@@ -15,26 +16,58 @@ var (
 type Users interface {
 	Create(u *models.User) error
 	GetByEmail(email string) (*models.User, error)
+	GetByLogin(login string) (*models.User, error)
 	Update(u *models.User) error
+	Delete(id int64) error
 	GetForToken(tokenScope, tokenPlaintext string) (*models.User, error)
 }
 
 type Pastes interface {
 	Create(p *models.Paste) error
-	Read(id uint16) (*models.Paste, error)
-	ReadAll(title string, category uint8, filters models.Filters) ([]*models.Paste, *models.Metadata, error)
+	Get(slug string) (*models.Paste, error)
+	Read(slug string) (*models.Paste, error)
+	ReadAll(search models.PasteSearch, filters models.Filters) ([]*models.Paste, *models.Metadata, error)
 	Update(p *models.Paste) error
-	Delete(id uint16) error
+	Transfer(p *models.Paste, ownerID int64) error
+	Delete(slug string) error
+	DeleteExpired(limit int) (int64, error)
 }
 
 type Tokens interface {
 	New(userID int64, ttl time.Duration, scope string) (*models.Token, error)
+	NewSession(userID int64, ttl time.Duration, userAgent, ip string) (*models.Token, error)
+	DeleteForToken(scope, tokenPlaintext string) error
 	DeleteAllForUser(scope string, userID int64) error
+	DeleteAll(userID int64) error
+	GetSessions(userID int64, currentToken string) ([]*models.Session, error)
+	DeleteSession(userID, id int64) error
+	Touch(tokenPlaintext string) error
+	DeleteExpired(limit int) (int64, error)
 }
 
 type Permissions interface {
-	SetWritePermission(userId int64, pasteId uint16) error
-	GetWritePermission(userId int64, pasteId uint16) (bool, error)
+	SetPermission(userId, pasteId int64, role string) (bool, error)
+	DeletePermission(userId, pasteId int64) error
+	GetCollaborators(pasteId int64) ([]*models.Collaborator, error)
+	GetWritePermission(userId int64, slug string) (bool, error)
+	IsOwner(userId int64, slug string) (bool, error)
+	IsCollaborator(userId, pasteId int64) (bool, error)
+}
+
+type Revisions interface {
+	GetAll(pasteId int64) ([]*models.Revision, error)
+	Get(pasteId int64, version uint32) (*models.Revision, error)
+}
+
+type Categories interface {
+	Insert(c *models.Category) error
+	GetAll() (models.Categories, error)
+	Update(c *models.Category) error
+	Delete(id uint8) error
+}
+
+type Tags interface {
+	GetAll(limit int) ([]*models.TagCount, error)
 }
 
 type Models struct {
@@ -42,13 +75,30 @@ type Models struct {
 	Users       Users
 	Tokens      Tokens
 	Permissions Permissions
+	Revisions   Revisions
+	Categories  Categories
+	Tags        Tags
+	Cursors     *models.CursorCodec
 }
 
-func NewModels(db *sql.DB) *Models {
+func NewModels(db *sql.DB, slugLength int, cursorKey []byte) (*Models, error) {
+	if slugLength < 1 || slugLength > models.MaxSlugLength {
+		return nil, fmt.Errorf("slug length must be between 1 and %d", models.MaxSlugLength)
+	}
+
+	cursors, err := models.NewCursorCodec(cursorKey)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Models{
-		Pastes:      &PasteModel{DB: db},
+		Pastes:      &PasteModel{DB: db, SlugLength: slugLength, Cursors: cursors},
 		Users:       &UserModel{DB: db},
 		Tokens:      &TokenModel{DB: db},
 		Permissions: &PermissionModel{DB: db},
-	}
+		Revisions:   &RevisionModel{DB: db},
+		Categories:  &CategoryModel{DB: db},
+		Tags:        &TagModel{DB: db},
+		Cursors:     cursors,
+	}, nil
 }
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"pasteAPI/internal/repository/models"
+	"time"
+)
+
+// RevisionModel reads the history of pastes. Revisions are written by PasteModel
+// in the same statement which creates or updates the paste.
+type RevisionModel struct {
+	DB *sql.DB
+}
+
+// GetAll returns revisions of the paste without their text, newest first.
+func (m *RevisionModel) GetAll(pasteId int64) ([]*models.Revision, error) {
+	query := `
+		SELECT version, title, created_at
+		FROM paste_revisions
+		WHERE paste_id = $1
+		ORDER BY version DESC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pasteId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	revisions := make([]*models.Revision, 0)
+
+	for rows.Next() {
+		var revision models.Revision
+
+		err := rows.Scan(
+			&revision.Version,
+			&revision.Title,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, &revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (m *RevisionModel) Get(pasteId int64, version uint32) (*models.Revision, error) {
+	query := `
+		SELECT version, title, text, created_at
+		FROM paste_revisions
+		WHERE paste_id = $1 AND version = $2`
+
+	var revision models.Revision
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, pasteId, version).Scan(
+		&revision.Version,
+		&revision.Title,
+		&revision.Text,
+		&revision.CreatedAt,
+	)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &revision, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"pasteAPI/internal/repository/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type TagModel struct {
+	DB *sql.DB
+}
+
+// GetAll returns up to limit tags of public pastes, the most used first.
+func (m *TagModel) GetAll(limit int) ([]*models.TagCount, error) {
+	query := `
+		SELECT tags.name, COUNT(*)
+		FROM tags
+		INNER JOIN paste_tags ON paste_tags.tag_id = tags.id
+		INNER JOIN pastes ON pastes.id = paste_tags.paste_id
+		WHERE pastes.visibility = 'public' AND pastes.expires_at >= NOW()
+		GROUP BY tags.name
+		ORDER BY COUNT(*) DESC, tags.name
+		LIMIT $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]*models.TagCount, 0)
+	for rows.Next() {
+		var tag models.TagCount
+
+		err = rows.Scan(&tag.Name, &tag.Pastes)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, &tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// setPasteTags replaces the tags of the paste, creating the ones which do not exist yet.
+// The tags are sorted, so concurrent transactions lock new tags in the same order.
+func setPasteTags(ctx context.Context, tx *sql.Tx, pasteId int64, tags []string) error {
+	query := `
+		INSERT INTO tags (name)
+		SELECT unnest($1::citext[])
+		ON CONFLICT (name) DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, pq.Array(tags))
+	if err != nil {
+		return err
+	}
+
+	query = `
+		DELETE FROM paste_tags
+		WHERE paste_id = $1 AND tag_id NOT IN (SELECT id FROM tags WHERE name = ANY($2::citext[]))`
+
+	_, err = tx.ExecContext(ctx, query, pasteId, pq.Array(tags))
+	if err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO paste_tags (paste_id, tag_id)
+		SELECT $1, id FROM tags WHERE name = ANY($2::citext[])
+		ON CONFLICT DO NOTHING`
+
+	_, err = tx.ExecContext(ctx, query, pasteId, pq.Array(tags))
+
+	return err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"pasteAPI/internal/repository/models"
 	"pasteAPI/pkg/validator"
@@ -11,6 +12,8 @@ import (
 const (
 	ScopeActivation     = "activation"
 	ScopeAuthentication = "authentication"
+	ScopeEmailChange    = "email-change"
+	ScopePasswordReset  = "password-reset"
 )
 
 func ValidateTokenPlaintext(v *validator.Validator, plaintext string) {
@@ -31,12 +34,24 @@ func (m TokenModel) New(userID int64, ttl time.Duration, scope string) (*models.
 	return token, err
 }
 
+// NewSession creates an authentication token for the client with the user agent and the IP.
+func (m TokenModel) NewSession(userID int64, ttl time.Duration, userAgent, ip string) (*models.Token, error) {
+	token, err := models.GenerateToken(userID, ttl, ScopeAuthentication)
+	if err != nil {
+		return nil, err
+	}
+	token.UserAgent, token.IP = userAgent, ip
+
+	err = m.Insert(token)
+	return token, err
+}
+
 func (m TokenModel) Insert(token *models.Token) error {
 	query := `
-		INSERT INTO tokens (hash, user_id, expiry, scope)
-		VALUES ($1, $2, $3, $4)`
+		INSERT INTO tokens (hash, user_id, expiry, scope, user_agent, ip)
+		VALUES ($1, $2, $3, $4, $5, $6)`
 
-	args := []interface{}{token.Hash, token.UserID, token.Expiry, token.Scope}
+	args := []interface{}{token.Hash, token.UserID, token.Expiry, token.Scope, token.UserAgent, token.IP}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -56,3 +71,144 @@ func (m TokenModel) DeleteAllForUser(scope string, userID int64) error {
 	_, err := m.DB.ExecContext(ctx, query, userID, scope)
 	return err
 }
+
+// DeleteForToken deletes the token with the plaintext in the scope.
+func (m TokenModel) DeleteForToken(scope, tokenPlaintext string) error {
+	query := `
+        DELETE FROM tokens
+        WHERE hash = $1 AND scope = $2`
+
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, tokenHash[:], scope)
+	return err
+}
+
+// GetSessions returns the unexpired authentication tokens of the user, the recently used first.
+// The token with the plaintext currentToken is marked as the current one.
+func (m TokenModel) GetSessions(userID int64, currentToken string) ([]*models.Session, error) {
+	query := `
+        SELECT id, created_at, last_used_at, expiry, user_agent, ip, hash = $3
+        FROM tokens
+        WHERE user_id = $1 AND scope = $2 AND expiry > NOW()
+        ORDER BY COALESCE(last_used_at, created_at) DESC, id DESC`
+
+	currentHash := sha256.Sum256([]byte(currentToken))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID, ScopeAuthentication, currentHash[:])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.Session, 0)
+	for rows.Next() {
+		var session models.Session
+
+		err = rows.Scan(
+			&session.ID,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+			&session.Expiry,
+			&session.UserAgent,
+			&session.IP,
+			&session.Current,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, &session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// DeleteSession revokes the authentication token with the ID if it belongs to the user.
+func (m TokenModel) DeleteSession(userID, id int64) error {
+	query := `
+        DELETE FROM tokens
+        WHERE id = $1 AND user_id = $2 AND scope = $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id, userID, ScopeAuthentication)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Touch records that the token with the plaintext has been used. The user agent and the IP
+// the token was created from are kept. Tokens used within the last minute are left as they are,
+// so busy clients do not write on every request.
+func (m TokenModel) Touch(tokenPlaintext string) error {
+	query := `
+        UPDATE tokens
+        SET last_used_at = NOW()
+        WHERE hash = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - interval '1 minute')`
+
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, tokenHash[:])
+	return err
+}
+
+// DeleteAll deletes the tokens of the user in every scope.
+func (m TokenModel) DeleteAll(userID int64) error {
+	query := `
+        DELETE FROM tokens
+        WHERE user_id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, userID)
+	return err
+}
+
+// DeleteExpired deletes up to limit expired tokens and returns how many were deleted.
+func (m TokenModel) DeleteExpired(limit int) (int64, error) {
+	query := `
+		DELETE FROM tokens
+		WHERE hash IN (
+			SELECT hash
+			FROM tokens
+			WHERE expiry <= NOW()
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
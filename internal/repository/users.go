@@ -42,7 +42,7 @@ func (m *UserModel) Create(user *models.User) error {
 
 func (m *UserModel) GetByEmail(email string) (*models.User, error) {
 	query := `
-        SELECT id, created_at, login, email, password_hash, activated, version
+        SELECT id, created_at, login, email, pending_email, password_hash, activated, is_admin, version
         FROM users
 		WHERE email = $1`
 
@@ -56,8 +56,45 @@ func (m *UserModel) GetByEmail(email string) (*models.User, error) {
 		&user.CreatedAt,
 		&user.Login,
 		&user.Email,
+		&user.PendingEmail,
 		&user.Password.Hash,
 		&user.Activated,
+		&user.Admin,
+		&user.Version,
+	)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &user, nil
+}
+
+func (m *UserModel) GetByLogin(login string) (*models.User, error) {
+	query := `
+        SELECT id, created_at, login, email, pending_email, password_hash, activated, is_admin, version
+        FROM users
+		WHERE login = $1`
+
+	var user models.User
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, login).Scan(
+		&user.ID,
+		&user.CreatedAt,
+		&user.Login,
+		&user.Email,
+		&user.PendingEmail,
+		&user.Password.Hash,
+		&user.Activated,
+		&user.Admin,
 		&user.Version,
 	)
 
@@ -76,7 +113,7 @@ func (m *UserModel) GetByEmail(email string) (*models.User, error) {
 func (m *UserModel) Update(user *models.User) error {
 	query := `
 	UPDATE users
-	SET login = $1, email = $2, password_hash = $3, activated = $4, version = version + 1
+	SET login = $1, email = $2, password_hash = $3, activated = $4, pending_email = $7, version = version + 1
 	WHERE id = $5 AND version = $6
 	RETURNING version`
 
@@ -87,6 +124,7 @@ func (m *UserModel) Update(user *models.User) error {
 		user.Activated,
 		user.ID,
 		user.Version,
+		user.PendingEmail,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -106,9 +144,35 @@ func (m *UserModel) Update(user *models.User) error {
 	return nil
 }
 
+// Delete deletes the user with their tokens and permissions. The pastes they own are kept
+// without an owner.
+func (m *UserModel) Delete(id int64) error {
+	query := `
+		DELETE FROM users
+		WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
 func (m *UserModel) GetForToken(tokenScope, tokenPlaintext string) (*models.User, error) {
 	query := `
-		SELECT users.id, users.created_at, users.login, users.email, users.password_hash, users.activated, users.version
+		SELECT users.id, users.created_at, users.login, users.email, users.pending_email, users.password_hash, users.activated, users.is_admin, users.version
         FROM users
 		INNER JOIN tokens 
 		ON users.id = tokens.user_id
@@ -126,8 +190,10 @@ func (m *UserModel) GetForToken(tokenScope, tokenPlaintext string) (*models.User
 		&user.CreatedAt,
 		&user.Login,
 		&user.Email,
+		&user.PendingEmail,
 		&user.Password.Hash,
 		&user.Activated,
+		&user.Admin,
 		&user.Version,
 	)
 
@@ -45,6 +45,7 @@ func Run(server *http.Server, service *service.Service) error {
 			shutdownError <- err
 		}
 		service.Logger.Info("completing background tasks")
+		service.Stop()
 		service.Wg.Wait()
 		shutdownError <- nil
 	}()
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"expvar"
+	"pasteAPI/internal/repository"
+	"time"
+)
+
+var (
+	purgedPastes = expvar.NewInt("janitor_purged_pastes")
+	purgedTokens = expvar.NewInt("janitor_purged_tokens")
+)
+
+// StartJanitor runs a background worker which periodically deletes expired pastes and tokens
+// in batches, so that no single query holds locks for long. The worker exits on Stop.
+func (s *Service) StartJanitor(pastes repository.Pastes, tokens repository.Tokens) error {
+	if !s.Config.Janitor.Enabled {
+		return nil
+	}
+
+	interval, err := time.ParseDuration(s.Config.Janitor.Interval)
+	if err != nil {
+		return err
+	}
+	if s.Config.Janitor.BatchSize <= 0 {
+		return errors.New("janitor batch size must be greater than zero")
+	}
+
+	s.Background(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-ticker.C:
+				s.purge("pastes", pastes.DeleteExpired, purgedPastes)
+				s.purge("tokens", tokens.DeleteExpired, purgedTokens)
+			}
+		}
+	})
+
+	return nil
+}
+
+func (s *Service) purge(table string, deleteExpired func(limit int) (int64, error), counter *expvar.Int) {
+	batchSize := s.Config.Janitor.BatchSize
+
+	var total int64
+	for {
+		select {
+		case <-s.quit:
+			return
+		default:
+		}
+
+		deleted, err := deleteExpired(batchSize)
+		if err != nil {
+			s.Logger.WithFields(map[string]interface{}{
+				"table": table,
+			}).Error(err)
+			break
+		}
+
+		total += deleted
+		counter.Add(deleted)
+
+		if deleted < int64(batchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.Logger.WithFields(map[string]interface{}{
+			"table":   table,
+			"deleted": total,
+		}).Info("purged expired records")
+	}
+}
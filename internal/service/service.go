@@ -12,6 +12,7 @@ type Service struct {
 	Logger *logrus.Logger
 	Mailer *mailer.Mailer
 	Wg     sync.WaitGroup
+	quit   chan struct{}
 }
 
 func New(cfg *config.Config, logger *logrus.Logger, mailer *mailer.Mailer) *Service {
@@ -20,6 +21,7 @@ func New(cfg *config.Config, logger *logrus.Logger, mailer *mailer.Mailer) *Serv
 		Logger: logger,
 		Mailer: mailer,
 		Wg:     sync.WaitGroup{},
+		quit:   make(chan struct{}),
 	}
 }
 
@@ -38,3 +40,8 @@ func (s *Service) Background(fn func()) {
 		fn()
 	}()
 }
+
+// Stop signals long-running background workers to finish. Wait for them with Wg.
+func (s *Service) Stop() {
+	close(s.quit)
+}
@@ -0,0 +1,6 @@
+ALTER TABLE pastes DROP CONSTRAINT IF EXISTS pastes_slug_key;
+ALTER TABLE pastes DROP COLUMN IF EXISTS slug;
+
+ALTER TABLE write_permissions ALTER COLUMN paste_id TYPE integer;
+ALTER SEQUENCE pastes_id_seq AS integer;
+ALTER TABLE pastes ALTER COLUMN id TYPE integer;
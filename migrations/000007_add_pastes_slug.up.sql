@@ -0,0 +1,8 @@
+ALTER TABLE pastes ALTER COLUMN id TYPE bigint;
+ALTER SEQUENCE pastes_id_seq AS bigint;
+ALTER TABLE write_permissions ALTER COLUMN paste_id TYPE bigint;
+
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS slug varchar(32);
+UPDATE pastes SET slug = substr(md5(random()::text || id::text), 1, 12) WHERE slug IS NULL;
+ALTER TABLE pastes ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE pastes ADD CONSTRAINT pastes_slug_key UNIQUE (slug);
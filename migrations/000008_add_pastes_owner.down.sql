@@ -0,0 +1,6 @@
+INSERT INTO write_permissions (paste_id, user_id)
+SELECT id, owner_id FROM pastes WHERE owner_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS pastes_owner_id_idx;
+ALTER TABLE pastes DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,12 @@
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS owner_id integer NULL REFERENCES users ON DELETE SET NULL;
+
+-- the only write permission ever granted so far belongs to the creator of a paste
+UPDATE pastes SET owner_id = write_permissions.user_id
+FROM write_permissions
+WHERE write_permissions.paste_id = pastes.id;
+
+DELETE FROM write_permissions
+USING pastes
+WHERE pastes.id = write_permissions.paste_id AND pastes.owner_id = write_permissions.user_id;
+
+CREATE INDEX IF NOT EXISTS pastes_owner_id_idx ON pastes (owner_id);
@@ -0,0 +1,3 @@
+ALTER TABLE write_permissions DROP CONSTRAINT IF EXISTS write_permissions_role_check;
+ALTER TABLE write_permissions DROP COLUMN IF EXISTS created_at;
+ALTER TABLE write_permissions DROP COLUMN IF EXISTS role;
@@ -0,0 +1,3 @@
+ALTER TABLE write_permissions ADD COLUMN IF NOT EXISTS role varchar(16) NOT NULL DEFAULT 'write';
+ALTER TABLE write_permissions ADD COLUMN IF NOT EXISTS created_at timestamp(0) with time zone NOT NULL DEFAULT NOW();
+ALTER TABLE write_permissions ADD CONSTRAINT write_permissions_role_check CHECK (role IN ('read', 'write'));
@@ -0,0 +1,2 @@
+ALTER TABLE pastes DROP COLUMN IF EXISTS visibility;
+DROP TYPE IF EXISTS paste_visibility;
@@ -0,0 +1,2 @@
+CREATE TYPE paste_visibility AS ENUM ('public', 'unlisted', 'private');
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS visibility paste_visibility NOT NULL DEFAULT 'public';
@@ -0,0 +1,4 @@
+ALTER TABLE pastes DROP CONSTRAINT IF EXISTS pastes_max_views_check;
+ALTER TABLE pastes DROP COLUMN IF EXISTS views;
+ALTER TABLE pastes DROP COLUMN IF EXISTS max_views;
+ALTER TABLE pastes DROP COLUMN IF EXISTS burn_after_read;
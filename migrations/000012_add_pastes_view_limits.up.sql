@@ -0,0 +1,4 @@
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS burn_after_read bool NOT NULL DEFAULT false;
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS max_views integer NULL;
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS views integer NOT NULL DEFAULT 0;
+ALTER TABLE pastes ADD CONSTRAINT pastes_max_views_check CHECK (max_views IS NULL OR max_views > 0);
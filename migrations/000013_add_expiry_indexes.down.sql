@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pastes_expires_at_idx;
+DROP INDEX IF EXISTS tokens_expiry_idx;
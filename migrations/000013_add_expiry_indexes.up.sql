@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS pastes_expires_at_idx ON pastes (expires_at);
+CREATE INDEX IF NOT EXISTS tokens_expiry_idx ON tokens (expiry);
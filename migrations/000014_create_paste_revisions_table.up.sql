@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS paste_revisions (
+    paste_id bigint NOT NULL REFERENCES pastes ON DELETE CASCADE,
+    version INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (paste_id, version)
+);
+
+INSERT INTO paste_revisions (paste_id, version, title, text, created_at)
+SELECT id, version, title, text, created_at FROM pastes
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW();
+
+UPDATE pastes SET updated_at = COALESCE(
+    (SELECT MAX(created_at) FROM paste_revisions WHERE paste_revisions.paste_id = pastes.id),
+    created_at
+);
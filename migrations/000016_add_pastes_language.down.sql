@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS pastes_language_idx;
+
+ALTER TABLE pastes DROP COLUMN IF EXISTS language;
@@ -0,0 +1,3 @@
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS language VARCHAR(32) NOT NULL DEFAULT 'plaintext';
+
+CREATE INDEX IF NOT EXISTS pastes_language_idx ON pastes (language);
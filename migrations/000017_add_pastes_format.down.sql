@@ -0,0 +1,2 @@
+ALTER TABLE pastes DROP COLUMN IF EXISTS format;
+DROP TYPE IF EXISTS paste_format;
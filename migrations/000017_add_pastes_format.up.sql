@@ -0,0 +1,2 @@
+CREATE TYPE paste_format AS ENUM ('plain', 'markdown');
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS format paste_format NOT NULL DEFAULT 'plain';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pastes_category_idx;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id smallserial PRIMARY KEY,
+    name citext UNIQUE NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    CONSTRAINT categories_id_check CHECK (id BETWEEN 1 AND 255),
+    CONSTRAINT categories_name_check CHECK (TRIM(name) != '')
+);
+
+-- the categories which used to be hard-coded keep their IDs
+INSERT INTO categories (id, name) VALUES (1, 'Sport'), (2, 'Home'), (3, 'Work')
+ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('categories', 'id'), (SELECT MAX(id) FROM categories));
+
+CREATE INDEX IF NOT EXISTS pastes_category_idx ON pastes (category);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id bigserial PRIMARY KEY,
+    name citext UNIQUE NOT NULL,
+    CONSTRAINT tags_name_check CHECK (LENGTH(name) BETWEEN 1 AND 32)
+);
+
+CREATE TABLE IF NOT EXISTS paste_tags (
+    paste_id bigint NOT NULL REFERENCES pastes ON DELETE CASCADE,
+    tag_id bigint NOT NULL REFERENCES tags ON DELETE CASCADE,
+    PRIMARY KEY (paste_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS paste_tags_tag_id_idx ON paste_tags (tag_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS pastes_search_vector_idx;
+
+ALTER TABLE pastes DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS pastes_title_russian_idx;
+DROP INDEX IF EXISTS pastes_title_english_idx;
+
+CREATE INDEX IF NOT EXISTS pastes_title_idx ON pastes USING GIN (to_tsvector('simple', title));
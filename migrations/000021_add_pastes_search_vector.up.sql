@@ -0,0 +1,16 @@
+-- the index built on the 'simple' config was never used by the title search
+DROP INDEX IF EXISTS pastes_title_idx;
+
+CREATE INDEX IF NOT EXISTS pastes_title_english_idx ON pastes USING GIN (to_tsvector('english', title));
+CREATE INDEX IF NOT EXISTS pastes_title_russian_idx ON pastes USING GIN (to_tsvector('russian', title));
+
+-- text of protected pastes and of pastes with limited views is not searchable,
+-- and only its beginning is indexed to stay within the tsvector size limit
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('russian', title), 'A') ||
+    setweight(to_tsvector('english', CASE WHEN password_hash IS NULL AND max_views IS NULL THEN left(text, 100000) ELSE '' END), 'B') ||
+    setweight(to_tsvector('russian', CASE WHEN password_hash IS NULL AND max_views IS NULL THEN left(text, 100000) ELSE '' END), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS pastes_search_vector_idx ON pastes USING GIN (search_vector);
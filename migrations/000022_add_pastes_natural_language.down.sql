@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS pastes_natural_language_idx;
+DROP INDEX IF EXISTS pastes_search_vector_idx;
+ALTER TABLE pastes DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE pastes ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('russian', title), 'A') ||
+    setweight(to_tsvector('english', CASE WHEN password_hash IS NULL AND max_views IS NULL THEN left(text, 100000) ELSE '' END), 'B') ||
+    setweight(to_tsvector('russian', CASE WHEN password_hash IS NULL AND max_views IS NULL THEN left(text, 100000) ELSE '' END), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS pastes_search_vector_idx ON pastes USING GIN (search_vector);
+
+ALTER TABLE pastes DROP COLUMN IF EXISTS natural_language;
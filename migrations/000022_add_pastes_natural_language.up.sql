@@ -0,0 +1,15 @@
+ALTER TABLE pastes ADD COLUMN IF NOT EXISTS natural_language regconfig NOT NULL DEFAULT 'english';
+
+-- the existing pastes were searched in English and Russian, so the Cyrillic ones are Russian
+UPDATE pastes SET natural_language = 'russian' WHERE title ~* '[а-яё]' OR left(text, 1000) ~* '[а-яё]';
+
+DROP INDEX IF EXISTS pastes_search_vector_idx;
+ALTER TABLE pastes DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE pastes ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(natural_language, title), 'A') ||
+    setweight(to_tsvector(natural_language, CASE WHEN password_hash IS NULL AND max_views IS NULL THEN left(text, 100000) ELSE '' END), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS pastes_search_vector_idx ON pastes USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS pastes_natural_language_idx ON pastes (natural_language);
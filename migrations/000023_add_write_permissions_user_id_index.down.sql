@@ -0,0 +1 @@
+DROP INDEX IF EXISTS write_permissions_user_id_idx;
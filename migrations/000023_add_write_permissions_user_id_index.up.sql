@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS write_permissions_user_id_idx ON write_permissions (user_id);
@@ -0,0 +1,8 @@
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS id bigserial;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS created_at timestamp(0) with time zone NOT NULL DEFAULT NOW();
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS last_used_at timestamp(0) with time zone NULL;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS user_agent varchar(256) NOT NULL DEFAULT '';
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS ip varchar(45) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS tokens_id_idx ON tokens (id);
+CREATE INDEX IF NOT EXISTS tokens_user_id_idx ON tokens (user_id);
@@ -0,0 +1 @@
+ALTER TABLE pastes DROP CONSTRAINT IF EXISTS pastes_category_fkey;
@@ -0,0 +1,3 @@
+-- rows written before the categories table existed are not checked, new and updated ones are
+ALTER TABLE pastes DROP CONSTRAINT IF EXISTS pastes_category_fkey;
+ALTER TABLE pastes ADD CONSTRAINT pastes_category_fkey FOREIGN KEY (category) REFERENCES categories (id) NOT VALID;
@@ -0,0 +1,268 @@
+// Package diff compares texts line by line and formats the result as a unified diff.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxEdits bounds the work of the Myers algorithm. Texts which differ by more lines
+// are reported as the replacement of everything between their common prefix and suffix.
+const maxEdits = 2000
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns the unified diff of texts a and b with the given number of context lines.
+// The result is empty if the texts are equal.
+func Unified(fromName, toName, a, b string, context int) string {
+	ops := lineDiff(splitLines(a), splitLines(b))
+
+	hunks := groupHunks(ops, context)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	for _, h := range hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.fromLine, h.fromCount), hunkRange(h.toLine, h.toCount))
+		for _, o := range ops[h.start:h.end] {
+			switch o.kind {
+			case opEqual:
+				sb.WriteByte(' ')
+			case opDelete:
+				sb.WriteByte('-')
+			case opInsert:
+				sb.WriteByte('+')
+			}
+			sb.WriteString(o.line)
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// lineDiff returns the shortest edit script turning a into b.
+func lineDiff(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{opEqual, line})
+	}
+
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{opEqual, line})
+	}
+
+	return ops
+}
+
+// myers implements the greedy algorithm from "An O(ND) Difference Algorithm and Its Variations".
+func myers(a, b []string) []op {
+	n, m := len(a), len(b)
+	limit := n + m
+	if limit > maxEdits {
+		limit = maxEdits
+	}
+
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+
+	// trace[d] holds the furthest reaching x for diagonals -d..d before the step d was made
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+
+	return replace(a, b)
+}
+
+func backtrack(trace [][]int, a, b []string) []op {
+	x, y := len(a), len(b)
+
+	var reversed []op
+	for d := len(trace) - 1; d >= 0; d-- {
+		// the snapshot covers diagonals -d..d, so the diagonal k is stored at k+d
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := 0
+		if d > 0 {
+			prevX = v[prevK+d]
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, op{opEqual, a[x]})
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			y--
+			reversed = append(reversed, op{opInsert, b[y]})
+		} else {
+			x--
+			reversed = append(reversed, op{opDelete, a[x]})
+		}
+	}
+
+	ops := make([]op, len(reversed))
+	for i := range reversed {
+		ops[i] = reversed[len(reversed)-1-i]
+	}
+	return ops
+}
+
+func replace(a, b []string) []op {
+	ops := make([]op, 0, len(a)+len(b))
+	for _, line := range a {
+		ops = append(ops, op{opDelete, line})
+	}
+	for _, line := range b {
+		ops = append(ops, op{opInsert, line})
+	}
+	return ops
+}
+
+type hunk struct {
+	start, end          int
+	fromLine, fromCount int
+	toLine, toCount     int
+}
+
+// groupHunks splits the edit script into hunks of changes surrounded by context lines.
+// Changes separated by no more than twice the context are merged into one hunk.
+func groupHunks(ops []op, context int) []hunk {
+	var hunks []hunk
+
+	// line numbers in a and b before the operation i
+	fromLines := make([]int, len(ops)+1)
+	toLines := make([]int, len(ops)+1)
+	for i, o := range ops {
+		fromLines[i+1], toLines[i+1] = fromLines[i], toLines[i]
+		if o.kind != opInsert {
+			fromLines[i+1]++
+		}
+		if o.kind != opDelete {
+			toLines[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opEqual {
+			i++
+			continue
+		}
+
+		start := max(i-context, 0)
+
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		end = min(end+context, len(ops))
+
+		hunks = append(hunks, hunk{
+			start:     start,
+			end:       end,
+			fromLine:  fromLines[start],
+			fromCount: fromLines[end] - fromLines[start],
+			toLine:    toLines[start],
+			toCount:   toLines[end] - toLines[start],
+		})
+
+		i = end
+	}
+
+	return hunks
+}
+
+// hunkRange formats the range in the way GNU diff does: the first line is 1-based,
+// an empty range refers to the line before it and a single line omits the count.
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line)
+	case 1:
+		return fmt.Sprintf("%d", line+1)
+	default:
+		return fmt.Sprintf("%d,%d", line+1, count)
+	}
+}
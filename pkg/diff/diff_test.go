@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{
+			name: "both empty",
+		},
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+		},
+		{
+			name: "trailing newline only",
+			a:    "a\nb",
+			b:    "a\nb\n",
+		},
+		{
+			name:    "from empty",
+			b:       "a\nb\n",
+			context: 3,
+			want:    "--- from\n+++ to\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "to empty",
+			a:       "a\nb\n",
+			context: 3,
+			want:    "--- from\n+++ to\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name:    "single line",
+			a:       "a",
+			b:       "b",
+			context: 3,
+			want:    "--- from\n+++ to\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+		{
+			name:    "change with context",
+			a:       "a\nb\nc\nd\ne\n",
+			b:       "a\nb\nX\nd\ne\n",
+			context: 1,
+			want:    "--- from\n+++ to\n@@ -2,3 +2,3 @@\n b\n-c\n+X\n d\n",
+		},
+		{
+			name:    "insertion without context",
+			a:       "a\nc\n",
+			b:       "a\nb\nc\n",
+			context: 0,
+			want:    "--- from\n+++ to\n@@ -1,0 +2 @@\n+b\n",
+		},
+		{
+			name:    "close changes share a hunk",
+			a:       "1\n2\n3\n4\n5\n",
+			b:       "X\n2\n3\n4\nY\n",
+			context: 2,
+			want:    "--- from\n+++ to\n@@ -1,5 +1,5 @@\n-1\n+X\n 2\n 3\n 4\n-5\n+Y\n",
+		},
+		{
+			name:    "distant changes split hunks",
+			a:       "1\n2\n3\n4\n5\n6\n7\n",
+			b:       "X\n2\n3\n4\n5\n6\nY\n",
+			context: 1,
+			want:    "--- from\n+++ to\n@@ -1,2 +1,2 @@\n-1\n+X\n 2\n@@ -6,2 +6,2 @@\n 6\n-7\n+Y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("from", "to", tt.a, tt.b, tt.context)
+			if got != tt.want {
+				t.Errorf("Unified() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		edits int
+	}{
+		{name: "empty", edits: 0},
+		{name: "equal", a: "abc", b: "abc", edits: 0},
+		{name: "insert all", b: "abc", edits: 3},
+		{name: "delete all", a: "abc", edits: 3},
+		{name: "paper example", a: "abcabba", b: "cbabac", edits: 5},
+		{name: "common prefix and suffix", a: "xxaxx", b: "xxbxx", edits: 2},
+		{name: "repeated lines", a: "aaaa", b: "aa", edits: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Split(tt.a, ""), strings.Split(tt.b, "")
+
+			ops := lineDiff(a, b)
+			checkScript(t, ops, a, b)
+
+			if got := countEdits(ops); got != tt.edits {
+				t.Errorf("edits = %d, want %d", got, tt.edits)
+			}
+		})
+	}
+}
+
+func TestLineDiffMaxEdits(t *testing.T) {
+	var a, b []string
+	for i := 0; i < maxEdits; i++ {
+		a = append(a, fmt.Sprintf("a%d", i))
+		b = append(b, fmt.Sprintf("b%d", i))
+	}
+	a = append([]string{"same"}, append(a, "same")...)
+	b = append([]string{"same"}, append(b, "same")...)
+
+	ops := lineDiff(a, b)
+	checkScript(t, ops, a, b)
+
+	// past the limit everything between the common prefix and suffix is replaced
+	for i, o := range ops[1 : len(ops)-1] {
+		want := opDelete
+		if i >= maxEdits {
+			want = opInsert
+		}
+		if o.kind != want {
+			t.Fatalf("op %d kind = %d, want %d", i+1, o.kind, want)
+		}
+	}
+}
+
+// checkScript fails the test unless the edit script turns a into b.
+func checkScript(t *testing.T, ops []op, a, b []string) {
+	t.Helper()
+
+	var from, to []string
+	for _, o := range ops {
+		if o.kind != opInsert {
+			from = append(from, o.line)
+		}
+		if o.kind != opDelete {
+			to = append(to, o.line)
+		}
+	}
+
+	if strings.Join(from, "\n") != strings.Join(a, "\n") || len(from) != len(a) {
+		t.Errorf("script source = %q, want %q", from, a)
+	}
+	if strings.Join(to, "\n") != strings.Join(b, "\n") || len(to) != len(b) {
+		t.Errorf("script result = %q, want %q", to, b)
+	}
+}
+
+func countEdits(ops []op) int {
+	edits := 0
+	for _, o := range ops {
+		if o.kind != opEqual {
+			edits++
+		}
+	}
+	return edits
+}
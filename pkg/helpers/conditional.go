@@ -0,0 +1,91 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MatchETag reports whether the list of entity tags from If-Match or If-None-Match header
+// contains the etag. Weak tags match only if weak comparison is allowed.
+func MatchETag(header, etag string, weak bool) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// NotModified reports whether the client already has the current representation according
+// to the If-None-Match or If-Modified-Since header. As RFC 9110 requires, If-Modified-Since
+// is ignored if If-None-Match is present. A zero lastModified disables the date check.
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return MatchETag(match, etag, true)
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// HTTP dates have a precision of one second
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// WriteNotModified writes 304 Not Modified with the validator and caching headers.
+func WriteNotModified(w http.ResponseWriter, headers http.Header) {
+	for key, value := range headers {
+		w.Header()[key] = value
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// WriteJSONWithETag works like WriteJSON, but tags the response with a weak entity tag
+// computed from the body and writes 304 Not Modified if the client sent a matching one.
+func WriteJSONWithETag(w http.ResponseWriter, r *http.Request, status int, data Envelope, headers http.Header) error {
+	js, err := marshalJSON(data)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(js)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	headers.Set("ETag", etag)
+
+	if NotModified(r, etag, time.Time{}) {
+		WriteNotModified(w, headers)
+		return nil
+	}
+
+	writeJSON(w, status, js, headers)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		etag   string
+		weak   bool
+		want   bool
+	}{
+		{name: "empty header", header: "", etag: `"a"`, want: false},
+		{name: "exact", header: `"a"`, etag: `"a"`, want: true},
+		{name: "different", header: `"b"`, etag: `"a"`, want: false},
+		{name: "unquoted", header: `a`, etag: `"a"`, want: false},
+		{name: "list", header: `"b", "a"`, etag: `"a"`, want: true},
+		{name: "list without spaces", header: `"b","a","c"`, etag: `"a"`, want: true},
+		{name: "list with extra spaces", header: ` "b" ,  "a" `, etag: `"a"`, want: true},
+		{name: "any", header: `*`, etag: `"a"`, want: true},
+		{name: "any in list", header: `"b", *`, etag: `"a"`, want: true},
+		{name: "weak candidate strong comparison", header: `W/"a"`, etag: `"a"`, weak: false, want: false},
+		{name: "weak candidate weak comparison", header: `W/"a"`, etag: `"a"`, weak: true, want: true},
+		{name: "weak etag weak comparison", header: `"a"`, etag: `W/"a"`, weak: true, want: true},
+		{name: "weak both weak comparison", header: `W/"a"`, etag: `W/"a"`, weak: true, want: true},
+		{name: "prefix only", header: `"a`, etag: `"a"`, want: false},
+		{name: "case sensitive", header: `"A"`, etag: `"a"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchETag(tt.header, tt.etag, tt.weak); got != tt.want {
+				t.Errorf("MatchETag(%q, %q, %v) = %v, want %v", tt.header, tt.etag, tt.weak, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name         string
+		method       string
+		headers      map[string]string
+		lastModified time.Time
+		want         bool
+	}{
+		{name: "no headers", want: false},
+		{name: "matching etag", headers: map[string]string{"If-None-Match": `"a"`}, want: true},
+		{name: "matching weak etag", headers: map[string]string{"If-None-Match": `W/"a"`}, want: true},
+		{name: "other etag", headers: map[string]string{"If-None-Match": `"b"`}, want: false},
+		{name: "HEAD", method: http.MethodHead, headers: map[string]string{"If-None-Match": `"a"`}, want: true},
+		{name: "not a safe method", method: http.MethodPatch, headers: map[string]string{"If-None-Match": `"a"`}, want: false},
+		{
+			name:         "same second",
+			headers:      map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			lastModified: modified,
+			want:         true,
+		},
+		{
+			name:         "modified since",
+			headers:      map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)},
+			lastModified: modified,
+			want:         false,
+		},
+		{
+			name:         "etag wins over date",
+			headers:      map[string]string{"If-None-Match": `"b"`, "If-Modified-Since": modified.Format(http.TimeFormat)},
+			lastModified: modified,
+			want:         false,
+		},
+		{
+			name:    "date check disabled",
+			headers: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			want:    false,
+		},
+		{
+			name:         "invalid date",
+			headers:      map[string]string{"If-Modified-Since": "yesterday"},
+			lastModified: modified,
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			r := httptest.NewRequest(method, "/", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			if got := NotModified(r, `"a"`, tt.lastModified); got != tt.want {
+				t.Errorf("NotModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONWithETag(t *testing.T) {
+	data := Envelope{"a": 1}
+
+	w := httptest.NewRecorder()
+	err := WriteJSONWithETag(w, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK, data, nil)
+	if err != nil {
+		t.Fatalf("WriteJSONWithETag() error = %v", err)
+	}
+
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.Len() == 0 {
+		t.Fatalf("WriteJSONWithETag() = %d with ETag %q, want 200 with an ETag and a body", w.Code, etag)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+
+	w = httptest.NewRecorder()
+	err = WriteJSONWithETag(w, r, http.StatusOK, data, nil)
+	if err != nil {
+		t.Fatalf("WriteJSONWithETag() error = %v", err)
+	}
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != etag {
+		t.Errorf("WriteJSONWithETag() = %d with body %q, want 304 without a body", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	err = WriteJSONWithETag(w, r, http.StatusOK, Envelope{"a": 2}, nil)
+	if err != nil {
+		t.Fatalf("WriteJSONWithETag() error = %v", err)
+	}
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("WriteJSONWithETag() = %d with ETag %q, want 200 with a new ETag", w.Code, w.Header().Get("ETag"))
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"pasteAPI/pkg/validator"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 type Envelope map[string]interface{}
@@ -21,14 +22,43 @@ func ReadIDParam(r *http.Request) (int64, error) {
 	return id, err
 }
 
+func ReadSlugParam(r *http.Request) (string, error) {
+	slug := chi.URLParam(r, "slug")
+	if !validator.Matches(slug, validator.SlugRX) {
+		return "", errors.New("invalid slug parameter")
+	}
+	return slug, nil
+}
+
+func ReadVersionParam(r *http.Request) (uint32, error) {
+	version, err := strconv.ParseUint(chi.URLParam(r, "version"), 10, 32)
+	if err != nil || version == 0 {
+		return 0, errors.New("invalid version parameter")
+	}
+	return uint32(version), nil
+}
+
 func WriteJSON(w http.ResponseWriter, status int, data Envelope, headers http.Header) error {
-	js, err := json.MarshalIndent(data, "", "\t")
+	js, err := marshalJSON(data)
 	if err != nil {
 		return err
 	}
 
-	js = append(js, '\n')
+	writeJSON(w, status, js, headers)
+
+	return nil
+}
+
+func marshalJSON(data Envelope) ([]byte, error) {
+	js, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return nil, err
+	}
 
+	return append(js, '\n'), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, js []byte, headers http.Header) {
 	for key, value := range headers {
 		w.Header()[key] = value
 	}
@@ -36,13 +66,15 @@ func WriteJSON(w http.ResponseWriter, status int, data Envelope, headers http.He
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	w.Write(js)
-
-	return nil
 }
 
 func ReadJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
-	maxBytes := 1_048_576
-	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
+	return ReadJSONLimited(w, r, dst, 1_048_576)
+}
+
+// ReadJSONLimited works like ReadJSON with the body limited to maxBytes.
+func ReadJSONLimited(w http.ResponseWriter, r *http.Request, dst interface{}, maxBytes int64) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 	err := dec.Decode(dst)
@@ -89,6 +121,68 @@ func ReadJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
 	return nil
 }
 
+// ReadText reads the whole body limited to maxBytes as UTF-8 text.
+func ReadText(w http.ResponseWriter, r *http.Request, maxBytes int64) (string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return "", fmt.Errorf("body must not be larger than %d bytes", maxBytes)
+		}
+		return "", err
+	}
+
+	if len(body) == 0 {
+		return "", errors.New("body must not be empty")
+	}
+	if !utf8.Valid(body) {
+		return "", errors.New("body must be UTF-8 encoded text")
+	}
+
+	return string(body), nil
+}
+
+// ReadFormFile parses the multipart/form-data body limited to maxBytes and reads the file
+// from the given field as UTF-8 text. The other form fields are available with r.FormValue.
+func ReadFormFile(w http.ResponseWriter, r *http.Request, field string, maxBytes int64) (string, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+	// the whole form fits in memory, so no temporary files are created
+	err := r.ParseMultipartForm(maxBytes)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return "", "", fmt.Errorf("body must not be larger than %d bytes", maxBytes)
+		}
+		return "", "", fmt.Errorf("body contains badly-formed multipart form: %w", err)
+	}
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return "", "", fmt.Errorf("form must contain the %s file", field)
+		}
+		return "", "", err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(content) == 0 {
+		return "", "", fmt.Errorf("%s must not be empty", field)
+	}
+	if !utf8.Valid(content) {
+		return "", "", fmt.Errorf("%s must be UTF-8 encoded text", field)
+	}
+
+	return string(content), header.Filename, nil
+}
+
 func ReadString(qs url.Values, key string, defaultValue string) string {
 	if value, exists := qs[key]; exists && len(value) > 0 {
 		return value[0]
@@ -117,3 +211,16 @@ func ReadInt(qs url.Values, key string, defaultValue int, v *validator.Validator
 	}
 	return defaultValue
 }
+
+// ReadBool reads a boolean value for the given key from the query string.
+// If the key does not exist or is not a valid boolean, it returns the default value.
+func ReadBool(qs url.Values, key string, defaultValue bool, v *validator.Validator) bool {
+	if value, exists := qs[key]; exists && len(value) > 0 {
+		if boolValue, err := strconv.ParseBool(value[0]); err == nil {
+			return boolValue
+		} else {
+			v.AddError(key, "must be a boolean value")
+		}
+	}
+	return defaultValue
+}
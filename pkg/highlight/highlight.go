@@ -0,0 +1,195 @@
+// Package highlight splits source code into lines of escaped HTML with the keywords,
+// strings, comments and numbers wrapped in spans, ready for a template to style them.
+package highlight
+
+import (
+	"html"
+	"html/template"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Classes of the spans produced by Lines.
+const (
+	ClassKeyword = "k"
+	ClassBuiltin = "b"
+	ClassString  = "s"
+	ClassComment = "c"
+	ClassNumber  = "n"
+)
+
+type token struct {
+	class string
+	text  string
+}
+
+// Lines returns the text split into lines of HTML highlighted according to the language.
+// Languages without a known syntax are escaped only.
+func Lines(language, text string) []template.HTML {
+	text = strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var tokens []token
+	if s, ok := syntaxes[language]; ok {
+		tokens = s.tokenize(text)
+	} else {
+		tokens = []token{{text: text}}
+	}
+
+	lines := []template.HTML{}
+
+	var line strings.Builder
+	for _, t := range tokens {
+		// tokens such as block comments span several lines, every line gets its own span
+		parts := strings.Split(t.text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, template.HTML(line.String()))
+				line.Reset()
+			}
+			if part == "" {
+				continue
+			}
+			if t.class == "" {
+				line.WriteString(html.EscapeString(part))
+				continue
+			}
+			line.WriteString(`<span class="` + t.class + `">`)
+			line.WriteString(html.EscapeString(part))
+			line.WriteString(`</span>`)
+		}
+	}
+
+	return append(lines, template.HTML(line.String()))
+}
+
+func (s *syntax) tokenize(text string) []token {
+	var tokens []token
+	plainStart := 0
+
+	emit := func(start, end int, class string) {
+		if plainStart < start {
+			tokens = append(tokens, token{text: text[plainStart:start]})
+		}
+		tokens = append(tokens, token{class: class, text: text[start:end]})
+		plainStart = end
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		prev, _ := utf8.DecodeLastRuneInString(text[:i])
+
+		if end, ok := s.comment(rest, i == 0 || unicode.IsSpace(prev)); ok {
+			emit(i, i+end, ClassComment)
+			i += end
+			continue
+		}
+
+		if end, ok := s.string(rest); ok {
+			emit(i, i+end, ClassString)
+			i += end
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(rest)
+		if isWordRune(prev, s.wordSymbols) {
+			i += size
+			continue
+		}
+
+		if unicode.IsDigit(r) {
+			end := wordEnd(rest, ".")
+			emit(i, i+end, ClassNumber)
+			i += end
+			continue
+		}
+
+		if isWordRune(r, s.wordSymbols) {
+			end := wordEnd(rest, s.wordSymbols)
+			word := rest[:end]
+			if s.caseInsensitive {
+				word = strings.ToLower(word)
+			}
+			switch {
+			case s.keywords[word]:
+				emit(i, i+end, ClassKeyword)
+			case s.builtins[word]:
+				emit(i, i+end, ClassBuiltin)
+			}
+			i += end
+			continue
+		}
+
+		i += size
+	}
+
+	if plainStart < len(text) {
+		tokens = append(tokens, token{text: text[plainStart:]})
+	}
+
+	return tokens
+}
+
+// comment returns the length of the comment at the start of the text. Comments starting
+// with # are recognised after a space only, so that they are not confused with $# or URLs.
+func (s *syntax) comment(text string, afterSpace bool) (int, bool) {
+	for _, block := range s.blockComments {
+		if strings.HasPrefix(text, block[0]) {
+			end := strings.Index(text[len(block[0]):], block[1])
+			if end < 0 {
+				return len(text), true
+			}
+			return len(block[0]) + end + len(block[1]), true
+		}
+	}
+
+	for _, prefix := range s.lineComments {
+		if strings.HasPrefix(text, prefix) && (afterSpace || prefix != "#") {
+			end := strings.IndexByte(text, '\n')
+			if end < 0 {
+				return len(text), true
+			}
+			return end, true
+		}
+	}
+
+	return 0, false
+}
+
+// string returns the length of the string literal at the start of the text. Only
+// multiline delimiters may span lines, an unterminated string ends with its line.
+func (s *syntax) string(text string) (int, bool) {
+	for _, d := range s.strings {
+		if !strings.HasPrefix(text, d.delimiter) {
+			continue
+		}
+
+		for i := len(d.delimiter); i < len(text); i++ {
+			switch {
+			case text[i] == '\\' && d.escapes:
+				i++
+			case text[i] == '\n' && !d.multiline:
+				return i, true
+			case strings.HasPrefix(text[i:], d.delimiter):
+				return i + len(d.delimiter), true
+			}
+		}
+
+		return len(text), true
+	}
+
+	return 0, false
+}
+
+func isWordRune(r rune, symbols string) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || (r < utf8.RuneSelf && strings.ContainsRune(symbols, r))
+}
+
+func wordEnd(text, symbols string) int {
+	for i, r := range text {
+		if !isWordRune(r, symbols) {
+			return i
+		}
+	}
+	return len(text)
+}
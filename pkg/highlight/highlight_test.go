@@ -0,0 +1,126 @@
+package highlight
+
+import (
+	"html/template"
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		text     string
+		want     []template.HTML
+	}{
+		{
+			name: "empty",
+			want: []template.HTML{""},
+		},
+		{
+			name:     "unknown language is escaped only",
+			language: "plaintext",
+			text:     "<b>if</b> & 1",
+			want:     []template.HTML{"&lt;b&gt;if&lt;/b&gt; &amp; 1"},
+		},
+		{
+			name:     "trailing newline",
+			language: "go",
+			text:     "a\nb\n",
+			want:     []template.HTML{"a", "b"},
+		},
+		{
+			name:     "CRLF line endings",
+			language: "go",
+			text:     "a\r\nb\r\n",
+			want:     []template.HTML{"a", "b"},
+		},
+		{
+			name:     "blank lines are kept",
+			language: "go",
+			text:     "a\n\nb",
+			want:     []template.HTML{"a", "", "b"},
+		},
+		{
+			name:     "keywords, builtins and numbers",
+			language: "go",
+			text:     "return len(x) + 42",
+			want:     []template.HTML{`<span class="k">return</span> <span class="b">len</span>(x) + <span class="n">42</span>`},
+		},
+		{
+			name:     "keywords inside words",
+			language: "go",
+			text:     "format x2 if_",
+			want:     []template.HTML{"format x2 if_"},
+		},
+		{
+			name:     "escaped string",
+			language: "go",
+			text:     `s := "a \"<b>\" // c"`,
+			want:     []template.HTML{`s := <span class="s">&#34;a \&#34;&lt;b&gt;\&#34; // c&#34;</span>`},
+		},
+		{
+			name:     "unterminated string ends with its line",
+			language: "go",
+			text:     "x := \"abc\nif",
+			want:     []template.HTML{`x := <span class="s">&#34;abc</span>`, `<span class="k">if</span>`},
+		},
+		{
+			name:     "raw string spans lines",
+			language: "go",
+			text:     "`a\nb`",
+			want:     []template.HTML{`<span class="s">` + "`a</span>", `<span class="s">b` + "`</span>"},
+		},
+		{
+			name:     "line comment",
+			language: "go",
+			text:     "x // if <y>\nif",
+			want:     []template.HTML{`x <span class="c">// if &lt;y&gt;</span>`, `<span class="k">if</span>`},
+		},
+		{
+			name:     "block comment spans lines",
+			language: "go",
+			text:     "/* a\nb */ go",
+			want:     []template.HTML{`<span class="c">/* a</span>`, `<span class="c">b */</span> <span class="k">go</span>`},
+		},
+		{
+			name:     "unterminated block comment",
+			language: "c",
+			text:     "/* a",
+			want:     []template.HTML{`<span class="c">/* a</span>`},
+		},
+		{
+			name:     "hash comment after space only",
+			language: "shell",
+			text:     "echo $# # count",
+			want:     []template.HTML{`<span class="b">echo</span> $# <span class="c"># count</span>`},
+		},
+		{
+			name:     "case insensitive keywords",
+			language: "sql",
+			text:     "SELECT 1",
+			want:     []template.HTML{`<span class="k">SELECT</span> <span class="n">1</span>`},
+		},
+		{
+			name:     "python triple quotes",
+			language: "python",
+			text:     `"""a "b" c"""`,
+			want:     []template.HTML{`<span class="s">&#34;&#34;&#34;a &#34;b&#34; c&#34;&#34;&#34;</span>`},
+		},
+		{
+			name:     "unicode identifiers",
+			language: "go",
+			text:     "имя := nil",
+			want:     []template.HTML{`имя := <span class="b">nil</span>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lines(tt.language, tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package highlight
+
+import "strings"
+
+type stringDelimiter struct {
+	delimiter string
+	escapes   bool
+	multiline bool
+}
+
+type syntax struct {
+	lineComments  []string
+	blockComments [][2]string
+	// strings are tried in order, so longer delimiters go first
+	strings         []stringDelimiter
+	keywords        map[string]bool
+	builtins        map[string]bool
+	caseInsensitive bool
+	// wordSymbols are the characters besides letters, digits and _ allowed in words
+	wordSymbols string
+}
+
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	doubleQuoted = stringDelimiter{delimiter: `"`, escapes: true}
+	singleQuoted = stringDelimiter{delimiter: `'`, escapes: true}
+	backQuoted   = stringDelimiter{delimiter: "`", escapes: true, multiline: true}
+	cStrings     = []stringDelimiter{doubleQuoted, singleQuoted}
+	cComments    = [][2]string{{"/*", "*/"}}
+)
+
+var syntaxes = map[string]*syntax{
+	"go": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       []stringDelimiter{doubleQuoted, singleQuoted, {delimiter: "`", multiline: true}},
+		keywords:      words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var"),
+		builtins:      words("append bool byte cap clear close complex copy delete error false float32 float64 int int8 int16 int32 int64 iota len make max min new nil panic print println real recover rune string true uint uint8 uint16 uint32 uint64 uintptr any"),
+	},
+	"python": {
+		lineComments: []string{"#"},
+		strings: []stringDelimiter{
+			{delimiter: `"""`, escapes: true, multiline: true},
+			{delimiter: `'''`, escapes: true, multiline: true},
+			doubleQuoted, singleQuoted,
+		},
+		keywords: words("and as assert async await break class continue def del elif else except finally for from global if import in is lambda match nonlocal not or pass raise return try while with yield case"),
+		builtins: words("True False None self print len range dict list set tuple str int float bool open super isinstance enumerate zip map filter"),
+	},
+	"javascript": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       []stringDelimiter{doubleQuoted, singleQuoted, backQuoted},
+		keywords:      words("async await break case catch class const continue debugger default delete do else export extends finally for function if import in instanceof let new of return static super switch this throw try typeof var void while with yield from"),
+		builtins:      words("true false null undefined NaN Infinity console window document require module exports Promise Object Array JSON Math"),
+		wordSymbols:   "$",
+	},
+	"typescript": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       []stringDelimiter{doubleQuoted, singleQuoted, backQuoted},
+		keywords:      words("abstract as async await break case catch class const continue declare default delete do else enum export extends finally for from function if implements import in instanceof interface keyof let namespace new of private protected public readonly return static super switch this throw try type typeof var void while yield"),
+		builtins:      words("true false null undefined string number boolean any unknown never object console Promise Array Record"),
+		wordSymbols:   "$",
+	},
+	"java": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       []stringDelimiter{{delimiter: `"""`, escapes: true, multiline: true}, doubleQuoted, singleQuoted},
+		keywords:      words("abstract assert break case catch class const continue default do else enum extends final finally for if implements import instanceof interface native new package private protected public record return static super switch synchronized this throw throws try var void volatile while"),
+		builtins:      words("true false null boolean byte char double float int long short String Object System"),
+	},
+	"c": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       cStrings,
+		keywords:      words("auto break case const continue default do else enum extern for goto if inline register restrict return sizeof static struct switch typedef union volatile while #include #define #ifdef #ifndef #endif #if #else #pragma"),
+		builtins:      words("char double float int long short signed unsigned void size_t NULL true false bool"),
+		wordSymbols:   "#",
+	},
+	"cpp": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       cStrings,
+		keywords:      words("auto break case catch class const constexpr continue default delete do else enum explicit extern for friend goto if inline namespace new noexcept operator private protected public return sizeof static struct switch template this throw try typedef typename union using virtual volatile while #include #define #ifdef #ifndef #endif #if #else #pragma"),
+		builtins:      words("bool char double float int long short signed unsigned void nullptr true false std string vector size_t"),
+		wordSymbols:   "#",
+	},
+	"csharp": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       cStrings,
+		keywords:      words("abstract as async await base break case catch class const continue default delegate do else enum event explicit extern finally fixed for foreach get if implicit in interface internal is lock namespace new operator out override params private protected public readonly ref return sealed set static struct switch this throw try typeof using var virtual void volatile while"),
+		builtins:      words("true false null bool byte char decimal double float int long object sbyte short string uint ulong ushort Console String Task"),
+	},
+	"rust": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       []stringDelimiter{doubleQuoted},
+		keywords:      words("as async await break const continue crate dyn else enum extern fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait type unsafe use where while"),
+		builtins:      words("true false bool char f32 f64 i8 i16 i32 i64 i128 isize str u8 u16 u32 u64 u128 usize String Vec Option Some None Result Ok Err Box"),
+	},
+	"ruby": {
+		lineComments: []string{"#"},
+		strings:      cStrings,
+		keywords:     words("alias and begin break case class def defined? do else elsif end ensure for if in module next not or redo rescue retry return self super then undef unless until when while yield require require_relative attr_accessor attr_reader attr_writer"),
+		builtins:     words("true false nil puts print p raise lambda proc"),
+		wordSymbols:  "?!",
+	},
+	"php": {
+		lineComments:  []string{"//", "#"},
+		blockComments: cComments,
+		strings:       cStrings,
+		keywords:      words("abstract and as break case catch class clone const continue declare default do echo else elseif empty enddeclare endfor endforeach endif endswitch endwhile extends final finally fn for foreach function global if implements include include_once instanceof interface isset list match namespace new or print private protected public readonly require require_once return static switch throw trait try unset use var while yield"),
+		builtins:      words("true false null TRUE FALSE NULL array string int float bool self parent"),
+		wordSymbols:   "$",
+	},
+	"shell": {
+		lineComments: []string{"#"},
+		strings:      []stringDelimiter{doubleQuoted, {delimiter: `'`, multiline: true}},
+		keywords:     words("if then else elif fi case esac for select while until do done in function time return exit export local readonly declare unset set shift source"),
+		builtins:     words("echo printf cd pwd read test true false exec eval trap"),
+	},
+	"sql": {
+		lineComments:    []string{"--"},
+		blockComments:   cComments,
+		strings:         []stringDelimiter{{delimiter: `'`, multiline: true}},
+		keywords:        words("add all alter and as asc begin between by case check column commit constraint create cross database default delete desc distinct drop else end exists foreign from full group having if in index inner insert into is join key left like limit not null offset on or order outer primary references returning right rollback select set table then transaction truncate union unique update using values view when where with"),
+		builtins:        words("true false count sum avg min max coalesce now integer int bigint smallint serial bigserial text varchar char boolean timestamp date numeric uuid jsonb"),
+		caseInsensitive: true,
+	},
+	"html": {
+		blockComments: [][2]string{{"<!--", "-->"}},
+		strings:       cStrings,
+	},
+	"xml": {
+		blockComments: [][2]string{{"<!--", "-->"}},
+		strings:       cStrings,
+	},
+	"css": {
+		blockComments: cComments,
+		strings:       cStrings,
+		keywords:      words("@media @import @font-face @keyframes @supports !important"),
+		wordSymbols:   "@!-",
+	},
+	"json": {
+		strings:  []stringDelimiter{doubleQuoted},
+		keywords: words("true false null"),
+	},
+	"yaml": {
+		lineComments: []string{"#"},
+		strings:      cStrings,
+		keywords:     words("true false null yes no on off"),
+	},
+	"dockerfile": {
+		lineComments:    []string{"#"},
+		strings:         cStrings,
+		keywords:        words("from as run cmd label expose env add copy entrypoint volume user workdir arg onbuild stopsignal healthcheck shell"),
+		caseInsensitive: true,
+	},
+	"makefile": {
+		lineComments: []string{"#"},
+		keywords:     words("ifeq ifneq ifdef ifndef else endif include define endef export override .PHONY"),
+		wordSymbols:  ".",
+	},
+	"perl": {
+		lineComments: []string{"#"},
+		strings:      cStrings,
+		keywords:     words("my our local sub if elsif else unless while until for foreach last next redo return use no package require do eval"),
+		builtins:     words("print printf say die warn open close shift push pop keys values defined scalar"),
+	},
+	"lua": {
+		blockComments: [][2]string{{"--[[", "]]"}},
+		lineComments:  []string{"--"},
+		strings:       cStrings,
+		keywords:      words("and break do else elseif end for function goto if in local not or repeat return then until while"),
+		builtins:      words("true false nil print pairs ipairs require table string math type tostring tonumber"),
+	},
+	"kotlin": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       []stringDelimiter{{delimiter: `"""`, multiline: true}, doubleQuoted, singleQuoted},
+		keywords:      words("as break by class companion const continue data do else enum for fun if import in interface is object open override package private protected public return sealed super this throw try typealias val var when while"),
+		builtins:      words("true false null Int Long String Boolean Double Float Unit Any List Map println"),
+	},
+	"swift": {
+		lineComments:  []string{"//"},
+		blockComments: cComments,
+		strings:       []stringDelimiter{{delimiter: `"""`, escapes: true, multiline: true}, doubleQuoted},
+		keywords:      words("as associatedtype break case catch class continue default defer do else enum extension fallthrough for func guard if import in init inout internal is let private protocol public repeat return self static struct subscript switch throw throws try var where while"),
+		builtins:      words("true false nil Int Double Float String Bool Array Dictionary Optional print"),
+	},
+}
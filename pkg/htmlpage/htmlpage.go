@@ -0,0 +1,70 @@
+// Package htmlpage renders standalone HTML pages from the templates embedded into the binary.
+package htmlpage
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+//go:embed "templates"
+var templateFS embed.FS
+
+var templates = template.Must(template.New("page").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Themes lists the color themes of the pages, the first one is the default.
+var Themes = []string{"light", "dark"}
+
+// Page holds what the header of every page shows. Nonce allows the inline style and script
+// of the page under the policy returned by ContentSecurityPolicy.
+type Page struct {
+	Title     string
+	Info      string
+	RawURL    string
+	Theme     string
+	Nonce     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Code is the page with highlighted source code, one line per table row.
+type Code struct {
+	Page
+	Lines []template.HTML
+}
+
+// Document is the page with a body of HTML rendered from Markdown. The body must already be sanitized.
+type Document struct {
+	Page
+	Body template.HTML
+}
+
+// Render writes the page defined by the template with the given name.
+func Render(w io.Writer, name string, data interface{}) error {
+	return templates.ExecuteTemplate(w, name, data)
+}
+
+// NewNonce returns a random value for the Nonce field of a page.
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// ContentSecurityPolicy forbids everything on the page except its own inline style and script
+// and, if allowed, images served over HTTPS.
+func ContentSecurityPolicy(nonce string, images bool) string {
+	policy := fmt.Sprintf("default-src 'none'; style-src 'nonce-%[1]s'; script-src 'nonce-%[1]s'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'", nonce)
+	if images {
+		policy += "; img-src https:"
+	}
+	return policy
+}
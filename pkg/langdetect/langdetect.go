@@ -0,0 +1,437 @@
+// Package langdetect guesses the programming language of a text from its file name,
+// its shebang line and the frequency of keywords typical for each language.
+package langdetect
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Plain is the language of texts which do not look like code.
+const Plain = "plaintext"
+
+// minScore is the lowest keyword score needed to tell a language apart from plain text.
+const minScore = 6
+
+// maxMatches bounds the score a single pattern can give, so one frequent keyword
+// does not outweigh the rest of the text.
+const maxMatches = 10
+
+// maxScanned is the length of the text prefix the keywords are searched in.
+const maxScanned = 64 << 10
+
+type pattern struct {
+	re     *regexp.Regexp
+	weight int
+}
+
+type language struct {
+	name string
+	// extensions are file name extensions, the first one is used for downloads
+	extensions []string
+	// filenames are whole file names such as Dockerfile
+	filenames []string
+	// interpreters are program names found in shebang lines
+	interpreters []string
+	patterns     []pattern
+}
+
+func p(expr string, weight int) pattern {
+	return pattern{re: regexp.MustCompile("(?m)" + expr), weight: weight}
+}
+
+var languages = []language{
+	{name: Plain, extensions: []string{".txt", ".text", ".log"}},
+	{
+		name:       "go",
+		extensions: []string{".go"},
+		patterns: []pattern{
+			p(`^package \w+\s*$`, 5),
+			p(`^import \($`, 4),
+			p(`\bfunc (\(\w+ \*?\w+\) )?\w+\(`, 3),
+			p(`\w+ :=`, 1),
+			p(`\bfmt\.\w+\(`, 2),
+			p(`\bdefer \w+`, 2),
+			p(`\bif err != nil \{`, 4),
+		},
+	},
+	{
+		name:         "python",
+		extensions:   []string{".py", ".pyw"},
+		interpreters: []string{"python"},
+		patterns: []pattern{
+			p(`^\s*def \w+\(.*\)( -> .+)?:\s*$`, 4),
+			p(`^\s*from [\w.]+ import \w+`, 4),
+			p(`^\s*import \w+(\.\w+)*\s*$`, 2),
+			p(`^\s*(elif|except)\b.*:\s*$`, 3),
+			p(`^\s*class \w+(\(.*\))?:\s*$`, 3),
+			p(`\bself\.\w+`, 1),
+			p(`\bNone\b`, 1),
+			p(`__name__ == ['"]__main__['"]`, 5),
+		},
+	},
+	{
+		name:         "javascript",
+		extensions:   []string{".js", ".mjs", ".cjs", ".jsx"},
+		interpreters: []string{"node", "nodejs"},
+		patterns: []pattern{
+			p(`\b(const|let|var) \w+ = `, 1),
+			p(`\bfunction\s*\w*\s*\(`, 2),
+			p(`\)\s*=>`, 1),
+			p(`\bconsole\.log\(`, 3),
+			p(`\brequire\(['"][\w./@-]+['"]\)`, 3),
+			p(`\bdocument\.\w+`, 2),
+			p(`===|!==`, 2),
+			p(`\bmodule\.exports\b`, 4),
+		},
+	},
+	{
+		name:         "typescript",
+		extensions:   []string{".ts", ".tsx"},
+		interpreters: []string{"ts-node", "deno"},
+		patterns: []pattern{
+			p(`\w\??: (string|number|boolean|any|void|unknown)\b`, 3),
+			p(`^\s*(export )?interface \w+\s*\{`, 3),
+			p(`^\s*(export )?type \w+ = `, 2),
+			p(`^import .+ from ['"][\w./@-]+['"];?$`, 1),
+			p(`\breadonly \w+`, 2),
+			p(`\b(public|private) \w+\(`, 1),
+		},
+	},
+	{
+		name:       "java",
+		extensions: []string{".java"},
+		patterns: []pattern{
+			p(`\bpublic (static )?(final )?(class|void|interface)\b`, 3),
+			p(`\bSystem\.out\.print`, 4),
+			p(`^import [\w.]+(\.\*)?;$`, 3),
+			p(`^package [\w.]+;$`, 4),
+			p(`@Override\b`, 3),
+			p(`\bString\[\] args\b`, 4),
+		},
+	},
+	{
+		name:       "c",
+		extensions: []string{".c", ".h"},
+		patterns: []pattern{
+			p(`^#include <\w+\.h>`, 4),
+			p(`^#include "[\w/]+\.h"`, 2),
+			p(`\bprintf\(`, 2),
+			p(`\b(malloc|free|sizeof)\(`, 2),
+			p(`\bint main\(`, 2),
+		},
+	},
+	{
+		name:       "cpp",
+		extensions: []string{".cpp", ".cc", ".cxx", ".hpp", ".hh"},
+		patterns: []pattern{
+			p(`^#include <\w+>`, 3),
+			p(`\bstd::\w+`, 4),
+			p(`\b(cout|cin|endl)\b`, 2),
+			p(`\btemplate\s*<`, 3),
+			p(`^\s*namespace \w+\s*\{`, 2),
+			p(`^using namespace \w+;`, 4),
+		},
+	},
+	{
+		name:       "csharp",
+		extensions: []string{".cs"},
+		patterns: []pattern{
+			p(`^using System(\.[\w.]+)?;`, 5),
+			p(`\bConsole\.Write(Line)?\(`, 4),
+			p(`\{\s*get;\s*(set;)?\s*\}`, 4),
+			p(`\b(public|private|internal) (static |async |override )*\w+ \w+\(`, 1),
+			p(`^\s*namespace [\w.]+`, 1),
+		},
+	},
+	{
+		name:       "rust",
+		extensions: []string{".rs"},
+		patterns: []pattern{
+			p(`\bfn \w+(<.*>)?\(`, 3),
+			p(`\blet mut\b`, 4),
+			p(`^\s*impl\b`, 3),
+			p(`\b(println|format|vec)!\(`, 4),
+			p(`^\s*use \w+::`, 3),
+			p(`&mut\b`, 2),
+		},
+	},
+	{
+		name:         "ruby",
+		extensions:   []string{".rb"},
+		filenames:    []string{"Gemfile", "Rakefile"},
+		interpreters: []string{"ruby"},
+		patterns: []pattern{
+			p(`^\s*def \w+[?!]?(\(.*\))?\s*$`, 2),
+			p(`^\s*end\s*$`, 1),
+			p(`\bputs\b`, 2),
+			p(`^\s*require(_relative)? ['"]`, 3),
+			p(`\bdo \|\w+(, \w+)*\|`, 4),
+			p(`\battr_(accessor|reader|writer)\b`, 4),
+		},
+	},
+	{
+		name:         "php",
+		extensions:   []string{".php"},
+		interpreters: []string{"php"},
+		patterns: []pattern{
+			p(`<\?php`, 10),
+			p(`\$\w+->\w+`, 2),
+			p(`\becho\b`, 1),
+			p(`\bfunction \w+\(\$`, 3),
+		},
+	},
+	{
+		name:         "shell",
+		extensions:   []string{".sh", ".bash", ".zsh"},
+		interpreters: []string{"sh", "bash", "zsh", "dash", "ksh"},
+		patterns: []pattern{
+			p(`\$\{\w+`, 2),
+			p(`^\s*(if|while|elif) \[\[? `, 3),
+			p(`^\s*(fi|done|esac)\s*$`, 3),
+			p(`^\s*echo\b`, 1),
+			p(`^\s*export \w+=`, 3),
+			p(`^\s*(sudo|apt|apt-get|curl|git|cd|mkdir|chmod|docker) `, 1),
+			p(`\|\s*(grep|awk|sed|xargs)\b`, 2),
+		},
+	},
+	{
+		name:       "sql",
+		extensions: []string{".sql"},
+		patterns: []pattern{
+			p(`(?i)\bselect\b[\s\S]+?\bfrom\b`, 4),
+			p(`(?i)\binsert into\b`, 4),
+			p(`(?i)\bcreate (table|index|view)\b`, 4),
+			p(`(?i)\balter table\b`, 4),
+			p(`(?i)\bupdate \w+ set\b`, 4),
+			p(`(?i)\b(where|join|group by|order by)\b`, 1),
+		},
+	},
+	{
+		name:       "html",
+		extensions: []string{".html", ".htm"},
+		patterns: []pattern{
+			p(`(?i)<!doctype html`, 10),
+			p(`(?i)</(div|span|body|head|html|p|a|script|ul|li|table)>`, 1),
+		},
+	},
+	{
+		name:       "css",
+		extensions: []string{".css"},
+		patterns: []pattern{
+			p(`^\s*[.#]?[\w-]+(\s*[,>+~]?\s*[.#]?[\w-]+)*\s*\{\s*$`, 1),
+			p(`^\s*(color|margin|padding|display|font-size|background|border|width|height):\s*[^;]+;\s*$`, 2),
+			p(`@media\b`, 3),
+		},
+	},
+	{name: "json", extensions: []string{".json"}},
+	{
+		name:       "yaml",
+		extensions: []string{".yml", ".yaml"},
+		patterns: []pattern{
+			p(`^---\s*$`, 2),
+			p(`^[\w-]+:\s*$`, 1),
+			p(`^\s*[\w-]+: \S`, 1),
+			p(`^\s+- [\w-]+:? `, 1),
+		},
+	},
+	{
+		name:       "xml",
+		extensions: []string{".xml"},
+		patterns: []pattern{
+			p(`^<\?xml `, 10),
+		},
+	},
+	{
+		name:       "markdown",
+		extensions: []string{".md", ".markdown"},
+		patterns: []pattern{
+			p(`^#{1,6} \S`, 1),
+			p(`^\s*[-*] \[[ x]\] `, 3),
+			p(`\[[^\]\n]+\]\([^)\n]+\)`, 3),
+			p("^```", 3),
+		},
+	},
+	{
+		name:      "dockerfile",
+		filenames: []string{"Dockerfile", "Containerfile"},
+		patterns: []pattern{
+			p(`^FROM \S+`, 4),
+			p(`^(RUN|COPY|ADD|CMD|ENTRYPOINT|WORKDIR|EXPOSE|ENV) `, 2),
+		},
+	},
+	{
+		name:      "makefile",
+		filenames: []string{"Makefile", "GNUmakefile"},
+		patterns: []pattern{
+			p(`^\.PHONY:`, 5),
+		},
+	},
+	{
+		name:         "perl",
+		extensions:   []string{".pl", ".pm"},
+		interpreters: []string{"perl"},
+		patterns: []pattern{
+			p(`^use (strict|warnings);`, 5),
+			p(`\bmy [$@%]\w+`, 4),
+		},
+	},
+	{
+		name:         "lua",
+		extensions:   []string{".lua"},
+		interpreters: []string{"lua"},
+		patterns: []pattern{
+			p(`\blocal \w+ = `, 3),
+			p(`\blocal function \w+`, 4),
+			p(`~=`, 2),
+		},
+	},
+	{
+		name:       "kotlin",
+		extensions: []string{".kt", ".kts"},
+		patterns: []pattern{
+			p(`\bfun \w+\(`, 4),
+			p(`\bval \w+(: \w+)? = `, 2),
+			p(`\bprintln\(`, 1),
+		},
+	},
+	{
+		name:       "swift",
+		extensions: []string{".swift"},
+		patterns: []pattern{
+			p(`^import (UIKit|Foundation|SwiftUI)$`, 5),
+			p(`\bguard let\b`, 4),
+			p(`\bfunc \w+\(.*\) -> `, 2),
+		},
+	},
+}
+
+var (
+	byName        = make(map[string]*language)
+	byExtension   = make(map[string]string)
+	byFilename    = make(map[string]string)
+	byInterpreter = make(map[string]string)
+	names         []string
+)
+
+func init() {
+	for i := range languages {
+		lang := &languages[i]
+		byName[lang.name] = lang
+		names = append(names, lang.name)
+		for _, ext := range lang.extensions {
+			byExtension[ext] = lang.name
+		}
+		for _, filename := range lang.filenames {
+			byFilename[filename] = lang.name
+		}
+		for _, interpreter := range lang.interpreters {
+			byInterpreter[interpreter] = lang.name
+		}
+	}
+	sort.Strings(names)
+}
+
+// Names returns the sorted names of all known languages.
+func Names() []string {
+	return append([]string(nil), names...)
+}
+
+// IsKnown reports whether the language is one of Names.
+func IsKnown(name string) bool {
+	_, ok := byName[name]
+	return ok
+}
+
+// Extension returns the usual file name extension of the language or .txt if it has none.
+func Extension(name string) string {
+	if lang, ok := byName[name]; ok && len(lang.extensions) > 0 {
+		return lang.extensions[0]
+	}
+	return ".txt"
+}
+
+// Detect guesses the language of the text. The filename is optional and is trusted first,
+// then the shebang line, then the keywords. Plain is returned if nothing fits.
+func Detect(filename, text string) string {
+	if name, ok := fromFilename(filename); ok {
+		return name
+	}
+	if name, ok := fromShebang(text); ok {
+		return name
+	}
+
+	trimmed := strings.TrimSpace(text)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return "json"
+	}
+
+	return fromKeywords(text)
+}
+
+func fromFilename(filename string) (string, bool) {
+	if filename == "" {
+		return "", false
+	}
+
+	base := path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	if name, ok := byFilename[base]; ok {
+		return name, true
+	}
+
+	name, ok := byExtension[strings.ToLower(path.Ext(base))]
+	return name, ok
+}
+
+// fromShebang recognises lines like "#!/bin/bash" and "#!/usr/bin/env python3".
+func fromShebang(text string) (string, bool) {
+	if !strings.HasPrefix(text, "#!") {
+		return "", false
+	}
+
+	line, _, _ := strings.Cut(text[2:], "\n")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	program := path.Base(fields[0])
+	if program == "env" {
+		program = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				program = field
+				break
+			}
+		}
+	}
+
+	// python3.12 is python
+	program = strings.TrimRight(program, "0123456789.")
+
+	name, ok := byInterpreter[program]
+	return name, ok
+}
+
+func fromKeywords(text string) string {
+	if len(text) > maxScanned {
+		text = text[:maxScanned]
+	}
+
+	best, bestScore := Plain, minScore-1
+	for i := range languages {
+		score := 0
+		for _, pt := range languages[i].patterns {
+			matches := len(pt.re.FindAllStringIndex(text, maxMatches))
+			score += matches * pt.weight
+		}
+		if score > bestScore {
+			best, bestScore = languages[i].name, score
+		}
+	}
+
+	return best
+}
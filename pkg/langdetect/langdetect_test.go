@@ -0,0 +1,115 @@
+package langdetect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		text     string
+		want     string
+	}{
+		{name: "empty", want: Plain},
+		{name: "prose", text: "Meeting notes: buy milk, call Bob, finish the report.", want: Plain},
+		{name: "extension", filename: "main.go", text: "anything", want: "go"},
+		{name: "uppercase extension", filename: "QUERY.SQL", text: "anything", want: "sql"},
+		{name: "directory in name", filename: "src/app/index.ts", text: "anything", want: "typescript"},
+		{name: "windows path", filename: `C:\src\app.py`, text: "anything", want: "python"},
+		{name: "whole file name", filename: "Dockerfile", text: "anything", want: "dockerfile"},
+		{name: "unknown extension falls back", filename: "notes.unknown", text: "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println(1)\n}\n", want: "go"},
+		{name: "filename beats content", filename: "notes.txt", text: "<?php echo 1;", want: Plain},
+		{name: "shebang", text: "#!/bin/bash\necho hi\n", want: "shell"},
+		{name: "env shebang with version", text: "#!/usr/bin/env python3.12\nprint(1)\n", want: "python"},
+		{name: "env shebang with flags", text: "#!/usr/bin/env -S node --no-warnings\nconsole.log(1)\n", want: "javascript"},
+		{name: "unknown shebang", text: "#!/usr/bin/awk -f\n{ print }\n", want: Plain},
+		{name: "bare shebang", text: "#!\nhello", want: Plain},
+		{name: "JSON object", text: `  {"a": [1, 2], "b": null}  `, want: "json"},
+		{name: "JSON array", text: `[1, 2, 3]`, want: "json"},
+		{name: "invalid JSON", text: `{"a": }`, want: Plain},
+		{
+			name: "go",
+			text: "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tx, err := run()\n\tif err != nil {\n\t\tfmt.Println(err)\n\t}\n}\n",
+			want: "go",
+		},
+		{
+			name: "python",
+			text: "from os import path\n\nclass A:\n    def run(self) -> None:\n        self.x = None\n\nif __name__ == '__main__':\n    A().run()\n",
+			want: "python",
+		},
+		{
+			name: "sql",
+			text: "CREATE TABLE users (id int);\nINSERT INTO users VALUES (1);\nSELECT id FROM users WHERE id = 1;\n",
+			want: "sql",
+		},
+		{
+			name: "php tag",
+			text: "<?php\n$user->name = 'x';\n",
+			want: "php",
+		},
+		{
+			name: "single weak keyword stays plain",
+			text: "we should order by date",
+			want: Plain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.filename, tt.text); got != tt.want {
+				t.Errorf("Detect(%q, ...) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectScansPrefixOnly(t *testing.T) {
+	text := strings.Repeat("lorem ipsum\n", maxScanned/12+1) + strings.Repeat("if err != nil {\n", 10)
+	if got := Detect("", text); got != Plain {
+		t.Errorf("Detect() = %q, want %q", got, Plain)
+	}
+}
+
+func TestExtension(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "go", want: ".go"},
+		{name: "javascript", want: ".js"},
+		{name: "dockerfile", want: ".txt"},
+		{name: Plain, want: ".txt"},
+		{name: "unknown", want: ".txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Extension(tt.name); got != tt.want {
+				t.Errorf("Extension(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	for i, name := range names {
+		if !IsKnown(name) {
+			t.Errorf("IsKnown(%q) = false", name)
+		}
+		if i > 0 && names[i-1] >= name {
+			t.Errorf("Names() is not sorted at %q", name)
+		}
+	}
+
+	names[0] = "changed"
+	if Names()[0] == "changed" {
+		t.Error("Names() returns the internal slice")
+	}
+
+	if IsKnown("") || IsKnown("Go") {
+		t.Error("IsKnown() accepts an unknown name")
+	}
+}
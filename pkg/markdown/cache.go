@@ -0,0 +1,79 @@
+package markdown
+
+import (
+	"container/list"
+	"html/template"
+	"sync"
+)
+
+// Cache keeps the rendered HTML of the latest version of up to size documents and evicts
+// the least recently used one when it is full. A Cache of size zero keeps nothing.
+// It is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[int64]*list.Element
+}
+
+type cacheEntry struct {
+	id      int64
+	version uint32
+	html    template.HTML
+}
+
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[int64]*list.Element),
+	}
+}
+
+// Get returns the HTML of the document if it was rendered for the same version.
+func (c *Cache) Get(id int64, version uint32) (template.HTML, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[id]
+	if !ok {
+		return "", false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if entry.version != version {
+		return "", false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return entry.html, true
+}
+
+// Put stores the HTML of the document version, replacing the one of an older version.
+func (c *Cache) Put(id int64, version uint32, html template.HTML) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[id]; ok {
+		entry := elem.Value.(*cacheEntry)
+		// a slow request must not replace a newer version
+		if entry.version <= version {
+			entry.version, entry.html = version, html
+		}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[id] = c.order.PushFront(&cacheEntry{id: id, version: version, html: html})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).id)
+	}
+}
@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		contains  []string
+		forbidden []string
+	}{
+		{
+			name:     "emphasis",
+			source:   "*a* **b**",
+			contains: []string{"<em>a</em>", "<strong>b</strong>"},
+		},
+		{
+			name:      "script tag",
+			source:    "before\n\n<script>alert(1)</script>\n\nafter",
+			contains:  []string{"before", "after"},
+			forbidden: []string{"<script", "alert(1)"},
+		},
+		{
+			name:      "javascript link",
+			source:    "[click](javascript:alert(1))",
+			forbidden: []string{"javascript:", "href"},
+		},
+		{
+			name:      "javascript link in raw HTML",
+			source:    `<a href="JaVaScRiPt:alert(1)">click</a>`,
+			forbidden: []string{"alert(1)", "href"},
+		},
+		{
+			name:      "event handler",
+			source:    `<img src="https://example.com/a.png" onerror="alert(1)">`,
+			contains:  []string{`src="https://example.com/a.png"`},
+			forbidden: []string{"onerror", "alert(1)"},
+		},
+		{
+			name:      "frames and styles",
+			source:    "<iframe src=\"https://example.com\"></iframe>\n\n<style>body{display:none}</style>\n\n<p style=\"color:red\">text</p>",
+			contains:  []string{"text"},
+			forbidden: []string{"<iframe", "<style", "style="},
+		},
+		{
+			name:     "fenced code language",
+			source:   "```go\nfmt.Println(\"<b>\")\n```",
+			contains: []string{`<code class="language-go">`, "&lt;b&gt;"},
+		},
+		{
+			name:      "arbitrary code class",
+			source:    `<code class="evil">x</code>`,
+			contains:  []string{"<code>x</code>"},
+			forbidden: []string{"evil"},
+		},
+		{
+			name:     "task list",
+			source:   "- [x] done\n- [ ] todo",
+			contains: []string{`type="checkbox"`, "checked", "disabled"},
+		},
+		{
+			name:      "input other than checkbox",
+			source:    `<input type="text" value="x">`,
+			forbidden: []string{`type="text"`},
+		},
+		{
+			name:     "external link",
+			source:   "[site](https://example.com)",
+			contains: []string{`href="https://example.com"`, `target="_blank"`, "noopener"},
+		},
+		{
+			name:      "relative link",
+			source:    "[page](/pastes)",
+			contains:  []string{`href="/pastes"`},
+			forbidden: []string{"_blank"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			for _, s := range tt.contains {
+				if !strings.Contains(string(got), s) {
+					t.Errorf("Render() = %q, want it to contain %q", got, s)
+				}
+			}
+			for _, s := range tt.forbidden {
+				if strings.Contains(strings.ToLower(string(got)), strings.ToLower(s)) {
+					t.Errorf("Render() = %q, want it not to contain %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	got, err := Render("")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("Render() = %q, want empty", got)
+	}
+}
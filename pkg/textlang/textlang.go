@@ -0,0 +1,84 @@
+// Package textlang guesses the natural language of a text from the share of Cyrillic
+// letters and the frequency of common words. The languages are named after the
+// PostgreSQL text search configurations used to index them.
+package textlang
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Simple is the configuration of texts in no particular language, it does not stem words.
+const Simple = "simple"
+
+// minStopwords is the lowest number of common words needed to tell a language apart.
+const minStopwords = 3
+
+// maxScanned is the length of the text prefix the words are counted in.
+const maxScanned = 64 << 10
+
+// stopwords are frequent words typical for each language written in Latin letters.
+var stopwords = map[string][]string{
+	"english":    {"the", "and", "is", "are", "of", "to", "in", "that", "it", "with", "for", "this", "you", "not", "be", "was", "have"},
+	"german":     {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "mit", "ich", "auf", "für", "von", "zu", "sich", "den", "dem"},
+	"french":     {"le", "la", "les", "et", "est", "des", "une", "un", "pas", "que", "pour", "dans", "avec", "sur", "ce", "qui", "du"},
+	"spanish":    {"el", "la", "los", "las", "y", "es", "que", "de", "en", "una", "por", "con", "para", "del", "se", "no", "como"},
+	"italian":    {"il", "di", "che", "è", "e", "la", "per", "non", "una", "sono", "con", "del", "della", "gli", "le", "un"},
+	"portuguese": {"o", "a", "os", "de", "que", "e", "não", "do", "da", "em", "um", "uma", "para", "com", "é", "são"},
+	"dutch":      {"de", "het", "een", "en", "van", "ik", "is", "niet", "dat", "op", "te", "zijn", "met", "voor", "er"},
+}
+
+var names = []string{Simple, "dutch", "english", "french", "german", "italian", "portuguese", "russian", "spanish"}
+
+// Names returns Simple followed by the other known languages in alphabetical order.
+func Names() []string {
+	return append([]string(nil), names...)
+}
+
+// IsKnown reports whether the language is one of Names.
+func IsKnown(name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect returns the language of the text, or Simple if none stands out.
+func Detect(text string) string {
+	if len(text) > maxScanned {
+		text = text[:maxScanned]
+	}
+
+	var cyrillic, latin int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if cyrillic > latin {
+		return "russian"
+	}
+
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		counts[word]++
+	}
+
+	best, bestScore := Simple, minStopwords-1
+	for _, name := range names {
+		score := 0
+		for _, word := range stopwords[name] {
+			score += counts[word]
+		}
+		if score > bestScore {
+			best, bestScore = name, score
+		}
+	}
+
+	return best
+}
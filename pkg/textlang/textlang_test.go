@@ -0,0 +1,68 @@
+package textlang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "empty", want: Simple},
+		{name: "code", text: "x := f(y)\nreturn x", want: Simple},
+		{name: "too few stopwords", text: "the cat sat", want: Simple},
+		{name: "english", text: "This is the story of a cat that sat on the mat and was happy.", want: "english"},
+		{name: "german", text: "Der Hund und die Katze sind nicht im Haus, das ist ein Problem.", want: "german"},
+		{name: "french", text: "Le chat et le chien sont dans la maison, ce n'est pas une surprise pour les enfants.", want: "french"},
+		{name: "spanish", text: "El perro y el gato están en la casa, es una sorpresa para los niños.", want: "spanish"},
+		{name: "russian", text: "Собака и кошка сидят дома.", want: "russian"},
+		{name: "russian with latin words", text: "Функция main вызывает run и печатает результат в консоль.", want: "russian"},
+		{name: "english with a russian word", text: "The word is привет and it is used in the greeting of the day.", want: "english"},
+		{name: "case insensitive", text: "THE CAT AND THE DOG ARE IN THE HOUSE.", want: "english"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectScansPrefixOnly(t *testing.T) {
+	text := strings.Repeat("x ", maxScanned/2+1) + strings.Repeat("the and is ", 10)
+	if got := Detect(text); got != Simple {
+		t.Errorf("Detect() = %q, want %q", got, Simple)
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if names[0] != Simple {
+		t.Errorf("Names()[0] = %q, want %q", names[0], Simple)
+	}
+	for i, name := range names {
+		if !IsKnown(name) {
+			t.Errorf("IsKnown(%q) = false", name)
+		}
+		if i > 1 && names[i-1] >= name {
+			t.Errorf("Names() is not sorted at %q", name)
+		}
+		if _, ok := stopwords[name]; !ok && name != Simple && name != "russian" {
+			t.Errorf("%q has no stopwords", name)
+		}
+	}
+
+	names[0] = "changed"
+	if Names()[0] == "changed" {
+		t.Error("Names() returns the internal slice")
+	}
+
+	if IsKnown("") || IsKnown("English") {
+		t.Error("IsKnown() accepts an unknown name")
+	}
+}
@@ -5,6 +5,7 @@ import "regexp"
 var (
 	EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 	LoginRX = regexp.MustCompile("^[A-Za-z0-9_-]{3,32}$")
+	SlugRX  = regexp.MustCompile("^[A-Za-z0-9]{1,32}$")
 )
 
 type Validator struct {